@@ -0,0 +1,259 @@
+package interactor
+
+import (
+	"context"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Audit outcomes.
+const (
+	AuditOutcomeSuccess = "success"
+	AuditOutcomeFailure = "failure"
+)
+
+// RedactedValue replaces the values of the fields tagged with `audit:"redact"`.
+const RedactedValue = "[REDACTED]"
+
+// AuditRecord describes who ran which request and with which outcome.
+type AuditRecord struct {
+	Principal     string      `json:"principal,omitempty"`
+	RequestType   string      `json:"request_type"`
+	Request       interface{} `json:"request,omitempty"`
+	Outcome       string      `json:"outcome"`
+	Error         string      `json:"error,omitempty"`
+	Timestamp     time.Time   `json:"timestamp"`
+	CorrelationID string      `json:"correlation_id,omitempty"`
+}
+
+// AuditSink stores audit records.
+type AuditSink interface {
+	Write(ctx context.Context, record AuditRecord) error
+}
+
+type correlationIDCtxKey struct{}
+
+// WithCorrelationID returns a copy of the context carrying the given correlation ID.
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDCtxKey{}, id)
+}
+
+// CorrelationIDFromContext returns the correlation ID stored in the context, if any.
+func CorrelationIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(correlationIDCtxKey{}).(string)
+
+	return id, ok
+}
+
+// AuditOption configures the Audit middleware.
+type AuditOption func(*auditConfig)
+
+type auditConfig struct {
+	excluded map[reflect.Type]bool
+	now      func() time.Time
+}
+
+// WithAuditExclude excludes the given request types from the audit trail, e.g. queries.
+func WithAuditExclude(reqs ...Request) AuditOption {
+	return func(cfg *auditConfig) {
+		for _, req := range reqs {
+			cfg.excluded[reflect.TypeOf(req)] = true
+		}
+	}
+}
+
+// WithAuditClock sets the function used to get the current time.
+func WithAuditClock(now func() time.Time) AuditOption {
+	return func(cfg *auditConfig) {
+		cfg.now = now
+	}
+}
+
+// Audit returns a middleware which writes an audit record for every run to the given sink.
+//
+// The principal and the correlation ID are taken from the context. The request payload is recorded with its
+// exported fields only. The fields tagged with `audit:"redact"` are replaced with RedactedValue and the ones
+// tagged with `audit:"-"` are omitted:
+//
+//	type ChangePassword struct {
+//		UserID   string
+//		Password string `audit:"redact"`
+//	}
+//
+// If the record cannot be written, ErrAuditFailed is returned along with the use case error, if any.
+// A run which panics is recorded as failed with ErrUseCasePanicked before the panic is re-raised.
+func Audit(sink AuditSink, opts ...AuditOption) Middleware {
+	cfg := &auditConfig{excluded: make(map[reflect.Type]bool), now: time.Now}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return func(next UseCaseRunnerFn) UseCaseRunnerFn {
+		return func(ctx context.Context, req Request, resp Response) error {
+			if cfg.excluded[reflect.TypeOf(req)] {
+				return next(ctx, req, resp)
+			}
+
+			defer func() {
+				if r := recover(); r != nil {
+					panicErr := fmt.Errorf("%w: %v", ErrUseCasePanicked, r)
+					_ = sink.Write(detach(ctx), newAuditRecord(ctx, req, panicErr, cfg.now()))
+
+					panic(r)
+				}
+			}()
+
+			runErr := next(ctx, req, resp)
+
+			if err := sink.Write(detach(ctx), newAuditRecord(ctx, req, runErr, cfg.now())); err != nil {
+				return errors.Join(runErr, fmt.Errorf("%w: %w", ErrAuditFailed, err))
+			}
+
+			return runErr
+		}
+	}
+}
+
+func newAuditRecord(ctx context.Context, req Request, runErr error, now time.Time) AuditRecord {
+	record := AuditRecord{
+		RequestType: RequestTypeKey(ctx, req),
+		Request:     redact(reflect.ValueOf(req)),
+		Outcome:     AuditOutcomeSuccess,
+		Timestamp:   now,
+	}
+
+	if principal, ok := PrincipalFromContext(ctx); ok {
+		record.Principal = principal.ID()
+	}
+
+	if id, ok := CorrelationIDFromContext(ctx); ok {
+		record.CorrelationID = id
+	}
+
+	if runErr != nil {
+		record.Outcome = AuditOutcomeFailure
+		record.Error = runErr.Error()
+	}
+
+	return record
+}
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// redact converts the value into plain maps and slices, leaving out unexported and excluded fields
+// and replacing redacted ones.
+func redact(v reflect.Value) interface{} {
+	switch v.Kind() {
+	case reflect.Invalid:
+		return nil
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+
+		return redact(v.Elem())
+	case reflect.Struct:
+		// Values which know how to encode themselves, e.g. time.Time, are kept as is,
+		// unless they have audit tags which the encoding would ignore.
+		encodesItself := v.Type().Implements(jsonMarshalerType) || v.Type().Implements(textMarshalerType)
+		if encodesItself && !hasAuditTags(v.Type(), make(map[reflect.Type]bool)) {
+			return v.Interface()
+		}
+
+		return redactStruct(v)
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+
+		items := make([]interface{}, v.Len())
+		for i := range items {
+			items[i] = redact(v.Index(i))
+		}
+
+		return items
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+
+		items := make(map[string]interface{}, v.Len())
+
+		iter := v.MapRange()
+		for iter.Next() {
+			items[fmt.Sprint(iter.Key())] = redact(iter.Value())
+		}
+
+		return items
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return nil
+	default:
+		return v.Interface()
+	}
+}
+
+// hasAuditTags tells whether the type or any type reachable through its exported fields has audit tags.
+func hasAuditTags(t reflect.Type, visited map[reflect.Type]bool) bool {
+	if visited[t] {
+		return false
+	}
+	visited[t] = true
+
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		return hasAuditTags(t.Elem(), visited)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+
+			if _, ok := field.Tag.Lookup("audit"); ok || hasAuditTags(field.Type, visited) {
+				return true
+			}
+		}
+	default:
+	}
+
+	return false
+}
+
+func redactStruct(v reflect.Value) map[string]interface{} {
+	fields := make(map[string]interface{}, v.NumField())
+
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		tag := field.Tag.Get("audit")
+		if tag == "-" {
+			continue
+		}
+
+		name := field.Name
+		if jsonName, _, _ := strings.Cut(field.Tag.Get("json"), ","); jsonName != "" && jsonName != "-" {
+			name = jsonName
+		}
+
+		if tag == "redact" {
+			fields[name] = RedactedValue
+
+			continue
+		}
+
+		fields[name] = redact(v.Field(i))
+	}
+
+	return fields
+}
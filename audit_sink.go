@@ -0,0 +1,93 @@
+package interactor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// MemoryAuditSink keeps audit records in memory.
+//
+// It is useful for tests.
+type MemoryAuditSink struct {
+	mu      sync.Mutex
+	records []AuditRecord
+}
+
+// NewMemoryAuditSink creates a new MemoryAuditSink instance.
+func NewMemoryAuditSink() *MemoryAuditSink {
+	return &MemoryAuditSink{}
+}
+
+// Write implements AuditSink interface.
+func (s *MemoryAuditSink) Write(_ context.Context, record AuditRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.records = append(s.records, record)
+
+	return nil
+}
+
+// Records returns the records written so far.
+func (s *MemoryAuditSink) Records() []AuditRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]AuditRecord(nil), s.records...)
+}
+
+// JSONLinesAuditSink writes audit records as JSON lines.
+type JSONLinesAuditSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewJSONLinesAuditSink creates a new JSONLinesAuditSink instance writing to w.
+func NewJSONLinesAuditSink(w io.Writer) *JSONLinesAuditSink {
+	return &JSONLinesAuditSink{w: w}
+}
+
+// OpenJSONLinesAuditFile opens the file at the given path for appending and returns a sink writing to it.
+//
+// The sink must be closed once it is no longer used.
+func OpenJSONLinesAuditFile(path string) (*JSONLinesAuditSink, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open audit file: %w", err)
+	}
+
+	return NewJSONLinesAuditSink(f), nil
+}
+
+// Write implements AuditSink interface.
+func (s *JSONLinesAuditSink) Write(_ context.Context, record AuditRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("cannot encode audit record: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.w.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("cannot write audit record: %w", err)
+	}
+
+	return nil
+}
+
+// Close closes the underlying writer if it is an io.Closer.
+func (s *JSONLinesAuditSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if c, ok := s.w.(io.Closer); ok {
+		return c.Close()
+	}
+
+	return nil
+}
@@ -0,0 +1,198 @@
+package interactor_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/interactor/v2"
+)
+
+var errSinkFailed = errors.New("sink failed")
+
+func TestAudit(t *testing.T) {
+	t.Parallel()
+
+	t.Run("a record is written for a successful run", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		clock := NewFakeClock()
+		sink := interactor.NewMemoryAuditSink()
+		runner := interactor.Chain(succeedingRunner, interactor.Audit(sink, interactor.WithAuditClock(clock.Now)))
+
+		ctx := interactor.WithPrincipal(context.Background(), &User{id: "123"})
+		ctx = interactor.WithCorrelationID(ctx, "abc")
+
+		// act
+		err := runner(ctx, ChangePassword{UserID: "456", Password: "secret", Token: "token"}, &ChangePasswordResponse{})
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, []interactor.AuditRecord{{
+			Principal:   "123",
+			RequestType: "interactor_test.ChangePassword",
+			Request: map[string]interface{}{
+				"user_id":  "456",
+				"Password": interactor.RedactedValue,
+			},
+			Outcome:       interactor.AuditOutcomeSuccess,
+			Timestamp:     clock.Now(),
+			CorrelationID: "abc",
+		}}, sink.Records())
+	})
+
+	t.Run("a record is written for a failed run", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		sink := interactor.NewMemoryAuditSink()
+		runner := interactor.Chain(failingRunner(errSomeErr), interactor.Audit(sink))
+
+		// act
+		err := runner(context.Background(), ChangePassword{}, &ChangePasswordResponse{})
+
+		// assert
+		require.ErrorIs(t, err, errSomeErr)
+		require.Len(t, sink.Records(), 1)
+		assert.Equal(t, interactor.AuditOutcomeFailure, sink.Records()[0].Outcome)
+		assert.Equal(t, errSomeErr.Error(), sink.Records()[0].Error)
+	})
+
+	t.Run("a record is written for a panicking run before the panic is re-raised", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		sink := interactor.NewMemoryAuditSink()
+		runner := interactor.Chain(func(context.Context, interactor.Request, interactor.Response) error {
+			panic("boom")
+		}, interactor.Audit(sink))
+
+		// act
+		act := func() { _ = runner(context.Background(), ChangePassword{}, &ChangePasswordResponse{}) }
+
+		// assert
+		assert.PanicsWithValue(t, "boom", act)
+		require.Len(t, sink.Records(), 1)
+		assert.Equal(t, interactor.AuditOutcomeFailure, sink.Records()[0].Outcome)
+		assert.Equal(t, "use case panicked: boom", sink.Records()[0].Error)
+	})
+
+	t.Run("a request encoding itself is redacted as well", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		sink := interactor.NewMemoryAuditSink()
+		runner := interactor.Chain(succeedingRunner, interactor.Audit(sink))
+
+		// act
+		err := runner(context.Background(), SelfEncodingLogin{Login: "jane", Password: "secret"}, &ChangePasswordResponse{})
+
+		// assert
+		require.NoError(t, err)
+		require.Len(t, sink.Records(), 1)
+		assert.Equal(t, map[string]interface{}{
+			"Login":    "jane",
+			"Password": interactor.RedactedValue,
+		}, sink.Records()[0].Request)
+	})
+
+	t.Run("excluded request types are not audited", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		sink := interactor.NewMemoryAuditSink()
+		runner := interactor.Chain(succeedingRunner, interactor.Audit(sink, interactor.WithAuditExclude(GetUser{})))
+
+		// act
+		err := runner(context.Background(), GetUser{}, &ChangePasswordResponse{})
+
+		// assert
+		require.NoError(t, err)
+		assert.Empty(t, sink.Records())
+	})
+
+	t.Run("when a record cannot be written, an error is returned", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		runner := interactor.Chain(succeedingRunner, interactor.Audit(failingAuditSink{}))
+
+		// act
+		err := runner(context.Background(), ChangePassword{}, &ChangePasswordResponse{})
+
+		// assert
+		require.ErrorIs(t, err, interactor.ErrAuditFailed)
+		require.ErrorIs(t, err, errSinkFailed)
+	})
+
+	t.Run("records are appended to a JSON lines file", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		path := filepath.Join(t.TempDir(), "audit.jsonl")
+
+		sink, err := interactor.OpenJSONLinesAuditFile(path)
+		require.NoError(t, err)
+
+		runner := interactor.Chain(succeedingRunner, interactor.Audit(sink))
+
+		// act
+		require.NoError(t, runner(context.Background(), ChangePassword{UserID: "1", Password: "secret"}, &ChangePasswordResponse{}))
+		require.NoError(t, runner(context.Background(), GetUser{UserID: "2"}, &ChangePasswordResponse{}))
+		require.NoError(t, sink.Close())
+
+		// assert
+		records := readAuditFile(t, path)
+		require.Len(t, records, 2)
+		assert.Equal(t, "interactor_test.ChangePassword", records[0].RequestType)
+		assert.Equal(t, map[string]interface{}{"user_id": "1", "Password": interactor.RedactedValue}, records[0].Request)
+		assert.Equal(t, "interactor_test.GetUser", records[1].RequestType)
+		assert.Equal(t, map[string]interface{}{"UserID": "2"}, records[1].Request)
+	})
+}
+
+type SelfEncodingLogin struct {
+	Login    string
+	Password string `audit:"redact"`
+}
+
+func (r SelfEncodingLogin) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]string{"login": r.Login, "password": r.Password})
+}
+
+type failingAuditSink struct{}
+
+func (failingAuditSink) Write(context.Context, interactor.AuditRecord) error {
+	return errSinkFailed
+}
+
+func readAuditFile(t *testing.T, path string) []interactor.AuditRecord {
+	t.Helper()
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+
+	defer f.Close()
+
+	var records []interactor.AuditRecord
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var record interactor.AuditRecord
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+
+		records = append(records, record)
+	}
+
+	require.NoError(t, scanner.Err())
+
+	return records
+}
@@ -0,0 +1,186 @@
+package interactor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Principal is the identity a use case is run on behalf of.
+type Principal interface {
+	ID() string
+}
+
+type principalCtxKey struct{}
+
+// WithPrincipal returns a copy of the context carrying the given principal.
+func WithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalCtxKey{}, principal)
+}
+
+// PrincipalFromContext returns the principal stored in the context, if any.
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalCtxKey{}).(Principal)
+
+	return principal, ok
+}
+
+// PrincipalAs returns the principal stored in the context if it has the given type.
+//
+//	user, ok := interactor.PrincipalAs[*User](ctx)
+func PrincipalAs[T Principal](ctx context.Context) (T, bool) {
+	principal, ok := ctx.Value(principalCtxKey{}).(T)
+
+	return principal, ok
+}
+
+// Policy decides whether the principal may run the request.
+//
+// It returns nil to allow the run. Any other error denies it and is reported wrapped into ErrForbidden,
+// unless it already is ErrForbidden or ErrUnauthenticated.
+type Policy func(ctx context.Context, principal Principal, req Request) error
+
+// AuthorizerOption configures an Authorizer.
+type AuthorizerOption func(*Authorizer)
+
+// WithStrictAuthorization denies the requests which have no policies registered.
+//
+// By default, such requests are allowed.
+func WithStrictAuthorization() AuthorizerOption {
+	return func(a *Authorizer) {
+		a.strict = true
+	}
+}
+
+// Authorizer evaluates the policies registered for requests against the principal stored in the context.
+type Authorizer struct {
+	strict bool
+
+	mu        sync.RWMutex
+	policies  map[reflect.Type][]Policy
+	families  []familyPolicies
+	anonymous map[reflect.Type]bool
+}
+
+type familyPolicies struct {
+	family   reflect.Type
+	policies []Policy
+}
+
+// NewAuthorizer creates a new Authorizer instance.
+func NewAuthorizer(opts ...AuthorizerOption) *Authorizer {
+	a := &Authorizer{policies: make(map[reflect.Type][]Policy), anonymous: make(map[reflect.Type]bool)}
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	return a
+}
+
+// Register registers the given policies for the provided request type.
+func (a *Authorizer) Register(req Request, policies ...Policy) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	reqType := reflect.TypeOf(req)
+	a.policies[reqType] = append(a.policies[reqType], policies...)
+}
+
+// AllowAnonymous allows the given request types to be run without a principal, e.g. logins or public queries.
+//
+// They are allowed even in strict mode if they have no policies. Their policies, if any, are still evaluated,
+// with a nil principal if the context has none.
+func (a *Authorizer) AllowAnonymous(reqs ...Request) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, req := range reqs {
+		a.anonymous[reflect.TypeOf(req)] = true
+	}
+}
+
+// RegisterFamily registers the given policies for every request implementing the provided interface.
+//
+// The interface is given as a nil pointer to it:
+//
+//	authorizer.RegisterFamily((*AdminRequest)(nil), requireAdmin)
+func (a *Authorizer) RegisterFamily(family interface{}, policies ...Policy) {
+	familyType := reflect.TypeOf(family)
+	if familyType == nil || familyType.Kind() != reflect.Ptr || familyType.Elem().Kind() != reflect.Interface {
+		panic(fmt.Sprintf("interactor: request family must be a pointer to an interface, %v given", familyType))
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.families = append(a.families, familyPolicies{family: familyType.Elem(), policies: policies})
+}
+
+// Authorize evaluates all the policies applicable to the request.
+//
+// It returns ErrUnauthenticated if there are policies to evaluate, but no principal in the context,
+// unless the request type is allowed to be run anonymously, see AllowAnonymous.
+// It returns ErrForbidden if any of the policies denies the run.
+func (a *Authorizer) Authorize(ctx context.Context, req Request) error {
+	policies, anonymous := a.policiesFor(req)
+	if len(policies) == 0 {
+		if a.strict && !anonymous {
+			return fmt.Errorf("%w: no policy for %T", ErrForbidden, req)
+		}
+
+		return nil
+	}
+
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok && !anonymous {
+		return fmt.Errorf("%w: %T", ErrUnauthenticated, req)
+	}
+
+	for _, policy := range policies {
+		err := policy(ctx, principal, req)
+		if err == nil {
+			continue
+		}
+
+		if errors.Is(err, ErrForbidden) || errors.Is(err, ErrUnauthenticated) {
+			return err
+		}
+
+		return fmt.Errorf("%w: %w", ErrForbidden, err)
+	}
+
+	return nil
+}
+
+// Middleware returns a middleware which runs the use case only if the request is authorized.
+func (a *Authorizer) Middleware() Middleware {
+	return func(next UseCaseRunnerFn) UseCaseRunnerFn {
+		return func(ctx context.Context, req Request, resp Response) error {
+			if err := a.Authorize(ctx, req); err != nil {
+				return err
+			}
+
+			return next(ctx, req, resp)
+		}
+	}
+}
+
+// policiesFor returns the policies applicable to the request and whether it may be run anonymously.
+func (a *Authorizer) policiesFor(req Request) ([]Policy, bool) {
+	reqType := reflect.TypeOf(req)
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	policies := append([]Policy(nil), a.policies[reqType]...)
+
+	for _, f := range a.families {
+		if reqType != nil && reqType.Implements(f.family) {
+			policies = append(policies, f.policies...)
+		}
+	}
+
+	return policies, a.anonymous[reqType]
+}
@@ -0,0 +1,252 @@
+package interactor_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/interactor/v2"
+)
+
+var errNotOwner = errors.New("not an owner")
+
+func TestAuthorizer(t *testing.T) {
+	t.Parallel()
+
+	requireAdmin := func(ctx context.Context, _ interactor.Principal, _ interactor.Request) error {
+		if user, ok := interactor.PrincipalAs[*User](ctx); ok && user.admin {
+			return nil
+		}
+
+		return interactor.ErrForbidden
+	}
+
+	testCases := []struct {
+		name      string
+		principal interactor.Principal
+		request   interactor.Request
+		register  func(a *interactor.Authorizer)
+		opts      []interactor.AuthorizerOption
+		wantErr   error
+	}{
+		{
+			name:     "a request with no policies is allowed",
+			request:  TestRequest{},
+			register: func(a *interactor.Authorizer) {},
+		},
+		{
+			name:     "in strict mode a request with no policies is denied",
+			request:  TestRequest{},
+			register: func(a *interactor.Authorizer) {},
+			opts:     []interactor.AuthorizerOption{interactor.WithStrictAuthorization()},
+			wantErr:  interactor.ErrForbidden,
+		},
+		{
+			name:    "a request with policies requires a principal",
+			request: TestRequest{},
+			register: func(a *interactor.Authorizer) {
+				a.Register(TestRequest{}, requireAdmin)
+			},
+			wantErr: interactor.ErrUnauthenticated,
+		},
+		{
+			name:      "a request is denied if a policy denies it",
+			principal: &User{id: "123"},
+			request:   TestRequest{},
+			register: func(a *interactor.Authorizer) {
+				a.Register(TestRequest{}, requireAdmin)
+			},
+			wantErr: interactor.ErrForbidden,
+		},
+		{
+			name:      "a request is allowed if all the policies allow it",
+			principal: &User{id: "123", admin: true},
+			request:   TestRequest{},
+			register: func(a *interactor.Authorizer) {
+				a.Register(TestRequest{}, requireAdmin, requireAdmin)
+			},
+			opts: []interactor.AuthorizerOption{interactor.WithStrictAuthorization()},
+		},
+		{
+			name:      "a custom policy error is preserved",
+			principal: &User{id: "123"},
+			request:   TestRequest{},
+			register: func(a *interactor.Authorizer) {
+				a.Register(TestRequest{}, func(context.Context, interactor.Principal, interactor.Request) error {
+					return errNotOwner
+				})
+			},
+			wantErr: errNotOwner,
+		},
+		{
+			name:    "in strict mode an anonymous request with no policies is allowed",
+			request: TestRequest{},
+			register: func(a *interactor.Authorizer) {
+				a.AllowAnonymous(TestRequest{})
+			},
+			opts: []interactor.AuthorizerOption{interactor.WithStrictAuthorization()},
+		},
+		{
+			name:    "the policies of an anonymous request are evaluated without a principal",
+			request: TestRequest{},
+			register: func(a *interactor.Authorizer) {
+				a.AllowAnonymous(TestRequest{})
+				a.Register(TestRequest{}, func(_ context.Context, principal interactor.Principal, _ interactor.Request) error {
+					if principal != nil {
+						return errNotOwner
+					}
+
+					return nil
+				})
+			},
+			opts: []interactor.AuthorizerOption{interactor.WithStrictAuthorization()},
+		},
+		{
+			name:      "family policies apply to every request implementing the interface",
+			principal: &User{id: "123"},
+			request:   DeleteUser{id: "456"},
+			register: func(a *interactor.Authorizer) {
+				a.RegisterFamily((*AdminRequest)(nil), requireAdmin)
+			},
+			wantErr: interactor.ErrForbidden,
+		},
+		{
+			name:      "family policies do not apply to other requests",
+			principal: &User{id: "123"},
+			request:   TestRequest{},
+			register: func(a *interactor.Authorizer) {
+				a.RegisterFamily((*AdminRequest)(nil), requireAdmin)
+			},
+			opts:    []interactor.AuthorizerOption{interactor.WithStrictAuthorization()},
+			wantErr: interactor.ErrForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// arrange
+			authorizer := interactor.NewAuthorizer(tc.opts...)
+			tc.register(authorizer)
+
+			ctx := context.Background()
+			if tc.principal != nil {
+				ctx = interactor.WithPrincipal(ctx, tc.principal)
+			}
+
+			// act
+			err := authorizer.Authorize(ctx, tc.request)
+
+			// assert
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+
+	t.Run("a custom policy error wraps ErrForbidden", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		authorizer := interactor.NewAuthorizer()
+		authorizer.Register(TestRequest{}, func(context.Context, interactor.Principal, interactor.Request) error {
+			return errNotOwner
+		})
+
+		ctx := interactor.WithPrincipal(context.Background(), &User{id: "123"})
+
+		// act
+		err := authorizer.Authorize(ctx, TestRequest{})
+
+		// assert
+		require.ErrorIs(t, err, interactor.ErrForbidden)
+	})
+
+	t.Run("a family must be given as a pointer to an interface", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Panics(t, func() {
+			interactor.NewAuthorizer().RegisterFamily(TestRequest{})
+		})
+	})
+
+	t.Run("the use case is not run if the request is denied", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		authorizer := interactor.NewAuthorizer(interactor.WithStrictAuthorization())
+		useCase := &PlaceOrderUseCase{}
+
+		dispatcher := interactor.NewDispatcher()
+		dispatcher.Use(authorizer.Middleware())
+		dispatcher.Register(PlaceOrder{}, interactor.MustAdapt(useCase))
+
+		// act
+		err := dispatcher.Run(context.Background(), PlaceOrder{}, &PlaceOrderResponse{})
+
+		// assert
+		require.ErrorIs(t, err, interactor.ErrForbidden)
+		assert.Zero(t, useCase.Placed())
+	})
+
+	t.Run("the use case is run if the request is allowed", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		authorizer := interactor.NewAuthorizer(interactor.WithStrictAuthorization())
+		authorizer.Register(PlaceOrder{}, func(context.Context, interactor.Principal, interactor.Request) error {
+			return nil
+		})
+
+		useCase := &PlaceOrderUseCase{}
+
+		dispatcher := interactor.NewDispatcher()
+		dispatcher.Use(authorizer.Middleware())
+		dispatcher.Register(PlaceOrder{}, interactor.MustAdapt(useCase))
+
+		ctx := interactor.WithPrincipal(context.Background(), &User{id: "123"})
+
+		// act
+		err := dispatcher.Run(ctx, PlaceOrder{}, &PlaceOrderResponse{})
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, 1, useCase.Placed())
+	})
+}
+
+func TestPrincipalFromContext(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no principal is stored by default", func(t *testing.T) {
+		t.Parallel()
+
+		_, ok := interactor.PrincipalFromContext(context.Background())
+		assert.False(t, ok)
+	})
+
+	t.Run("the stored principal is returned", func(t *testing.T) {
+		t.Parallel()
+
+		want := &User{id: "123"}
+		ctx := interactor.WithPrincipal(context.Background(), want)
+
+		got, ok := interactor.PrincipalFromContext(ctx)
+		require.True(t, ok)
+		assert.Same(t, want, got)
+
+		user, ok := interactor.PrincipalAs[*User](ctx)
+		require.True(t, ok)
+		assert.Same(t, want, user)
+	})
+}
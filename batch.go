@@ -0,0 +1,150 @@
+package interactor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Call is a request to run along with the response to write the result to.
+type Call struct {
+	Request  Request
+	Response Response
+}
+
+// CallResult is the outcome of a Call.
+type CallResult struct {
+	Response Response
+	Err      error
+}
+
+// BatchOptions configures RunBatch.
+type BatchOptions struct {
+	// Concurrency is the maximum number of calls run at once. Zero means no limit.
+	Concurrency int
+	// FailFast stops the batch on the first failure: the running calls have their context cancelled.
+	//
+	// By default, all the calls are run and all the failures are collected.
+	FailFast bool
+}
+
+// BatchError reports the calls of a batch which failed.
+type BatchError struct {
+	// Results holds the outcomes of all the calls in the input order.
+	Results []CallResult
+}
+
+// Failed returns the indexes of the failed calls.
+func (e *BatchError) Failed() []int {
+	var failed []int
+
+	for i, result := range e.Results {
+		if result.Err != nil {
+			failed = append(failed, i)
+		}
+	}
+
+	return failed
+}
+
+func (e *BatchError) Error() string {
+	failed := e.Failed()
+
+	msgs := make([]string, len(failed))
+	for i, idx := range failed {
+		msgs[i] = fmt.Sprintf("#%d: %v", idx, e.Results[idx].Err)
+	}
+
+	return fmt.Sprintf("%d of %d calls failed: %s", len(failed), len(e.Results), strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the errors of the failed calls, so they can be checked with errors.Is and errors.As.
+func (e *BatchError) Unwrap() []error {
+	var errs []error
+
+	for _, result := range e.Results {
+		if result.Err != nil {
+			errs = append(errs, result.Err)
+		}
+	}
+
+	return errs
+}
+
+// RunBatch runs the given calls with bounded concurrency.
+//
+// It returns the outcome of every call in the input order. If any of the calls fails, it returns *BatchError as well.
+// The calls which have not started by the time the context is done, or the batch fails fast, are not run
+// and fail with ErrBatchAborted. A call which panics fails with ErrUseCasePanicked.
+func (d *Dispatcher) RunBatch(ctx context.Context, calls []Call, opts BatchOptions) ([]CallResult, error) {
+	results := runCalls(ctx, d.Run, calls, opts)
+
+	return results, batchError(results)
+}
+
+// batchError returns *BatchError if any of the calls failed.
+func batchError(results []CallResult) error {
+	for _, result := range results {
+		if result.Err != nil {
+			return &BatchError{Results: results}
+		}
+	}
+
+	return nil
+}
+
+func runCalls(ctx context.Context, runner UseCaseRunnerFn, calls []Call, opts BatchOptions) []CallResult {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	concurrency := opts.Concurrency
+	if concurrency <= 0 || concurrency > len(calls) {
+		concurrency = len(calls)
+	}
+
+	results := make([]CallResult, len(calls))
+	slots := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+
+	for i, call := range calls {
+		results[i].Response = call.Response
+
+		if !acquireSlot(ctx, slots) {
+			results[i].Err = fmt.Errorf("%w: %w", ErrBatchAborted, ctx.Err())
+
+			continue
+		}
+
+		wg.Add(1)
+
+		go func(i int, call Call) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			results[i].Err = runRecovered(ctx, runner, call.Request, call.Response)
+			if results[i].Err != nil && opts.FailFast {
+				cancel()
+			}
+		}(i, call)
+	}
+
+	wg.Wait()
+
+	return results
+}
+
+// acquireSlot takes a slot unless the context is done first.
+func acquireSlot(ctx context.Context, slots chan struct{}) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	select {
+	case slots <- struct{}{}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
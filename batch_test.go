@@ -0,0 +1,200 @@
+package interactor_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/interactor/v2"
+)
+
+func TestDispatcherRunBatch(t *testing.T) {
+	t.Parallel()
+
+	t.Run("all the calls are run and their results returned in input order", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		dispatcher := interactor.NewDispatcher()
+		dispatcher.Register(TestRequest{}, interactor.MustAdapt(ConcreteUseCase{}))
+
+		calls := testCalls(10)
+
+		// act
+		results, err := dispatcher.RunBatch(context.Background(), calls, interactor.BatchOptions{Concurrency: 3})
+
+		// assert
+		require.NoError(t, err)
+		require.Len(t, results, len(calls))
+		for i, result := range results {
+			require.NoError(t, result.Err)
+			assert.Equal(t, &TestResponse{result: i}, result.Response)
+		}
+	})
+
+	t.Run("no more calls than the concurrency limit are run at once", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		var running, maxRunning int32
+
+		dispatcher := interactor.NewDispatcher()
+		dispatcher.Register(TestRequest{}, func(context.Context, interactor.Request, interactor.Response) error {
+			n := atomic.AddInt32(&running, 1)
+			defer atomic.AddInt32(&running, -1)
+
+			for {
+				highest := atomic.LoadInt32(&maxRunning)
+				if n <= highest || atomic.CompareAndSwapInt32(&maxRunning, highest, n) {
+					return nil
+				}
+			}
+		})
+
+		// act
+		_, err := dispatcher.RunBatch(context.Background(), testCalls(50), interactor.BatchOptions{Concurrency: 2})
+
+		// assert
+		require.NoError(t, err)
+		assert.LessOrEqual(t, atomic.LoadInt32(&maxRunning), int32(2))
+	})
+
+	t.Run("all the failures are collected", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		dispatcher := interactor.NewDispatcher()
+		dispatcher.Register(TestRequest{}, func(_ context.Context, req interactor.Request, _ interactor.Response) error {
+			if req.(TestRequest).id%2 == 1 {
+				return errSomeErr
+			}
+
+			return nil
+		})
+
+		// act
+		results, err := dispatcher.RunBatch(context.Background(), testCalls(5), interactor.BatchOptions{Concurrency: 2})
+
+		// assert
+		require.ErrorIs(t, err, errSomeErr)
+
+		var batchErr *interactor.BatchError
+		require.True(t, errors.As(err, &batchErr))
+		assert.Equal(t, []int{1, 3}, batchErr.Failed())
+		assert.Equal(t, results, batchErr.Results)
+		assert.Equal(t, "2 of 5 calls failed: #1: some error; #3: some error", err.Error())
+	})
+
+	t.Run("a panicking call fails on its own", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		dispatcher := interactor.NewDispatcher()
+		dispatcher.Register(TestRequest{}, func(_ context.Context, req interactor.Request, _ interactor.Response) error {
+			if req.(TestRequest).id == 1 {
+				panic("boom")
+			}
+
+			return nil
+		})
+
+		// act
+		results, err := dispatcher.RunBatch(context.Background(), testCalls(3), interactor.BatchOptions{})
+
+		// assert
+		require.ErrorIs(t, err, interactor.ErrUseCasePanicked)
+		require.ErrorIs(t, results[1].Err, interactor.ErrUseCasePanicked)
+		require.NoError(t, results[0].Err)
+		require.NoError(t, results[2].Err)
+	})
+
+	t.Run("in fail fast mode the calls not started yet are aborted", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		dispatcher := interactor.NewDispatcher()
+		dispatcher.Register(TestRequest{}, interactor.MustAdapt(ConcreteUseCase{err: errSomeErr}))
+
+		// act
+		results, err := dispatcher.RunBatch(context.Background(), testCalls(5), interactor.BatchOptions{Concurrency: 1, FailFast: true})
+
+		// assert
+		require.ErrorIs(t, err, errSomeErr)
+		require.ErrorIs(t, results[0].Err, errSomeErr)
+		for _, result := range results[1:] {
+			require.ErrorIs(t, result.Err, interactor.ErrBatchAborted)
+		}
+	})
+
+	t.Run("in fail fast mode the running calls are cancelled", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		useCase := NewBlockingUseCase()
+		defer useCase.Release()
+
+		dispatcher := interactor.NewDispatcher()
+		dispatcher.Register(TestRequest{}, interactor.MustAdapt(useCase))
+		dispatcher.Register(&TestRequest{}, func(context.Context, interactor.Request, interactor.Response) error {
+			useCase.Started(1)
+
+			return errSomeErr
+		})
+
+		calls := []interactor.Call{
+			{Request: TestRequest{}, Response: &TestResponse{}},
+			{Request: &TestRequest{}, Response: &TestResponse{}},
+		}
+
+		// act
+		results, err := dispatcher.RunBatch(context.Background(), calls, interactor.BatchOptions{FailFast: true})
+
+		// assert
+		require.ErrorIs(t, err, errSomeErr)
+		require.ErrorIs(t, results[0].Err, context.Canceled)
+	})
+
+	t.Run("when the context is done, the calls not started yet are aborted", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		dispatcher := interactor.NewDispatcher()
+		dispatcher.Register(TestRequest{}, interactor.MustAdapt(ConcreteUseCase{}))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		// act
+		results, err := dispatcher.RunBatch(ctx, testCalls(3), interactor.BatchOptions{})
+
+		// assert
+		require.ErrorIs(t, err, interactor.ErrBatchAborted)
+		for _, result := range results {
+			require.ErrorIs(t, result.Err, context.Canceled)
+		}
+	})
+
+	t.Run("an empty batch succeeds", func(t *testing.T) {
+		t.Parallel()
+
+		// act
+		results, err := interactor.NewDispatcher().RunBatch(context.Background(), nil, interactor.BatchOptions{})
+
+		// assert
+		require.NoError(t, err)
+		assert.Empty(t, results)
+	})
+}
+
+func testCalls(n int) []interactor.Call {
+	calls := make([]interactor.Call, n)
+	for i := range calls {
+		calls[i] = interactor.Call{Request: TestRequest{id: i}, Response: &TestResponse{}}
+	}
+
+	return calls
+}
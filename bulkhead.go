@@ -0,0 +1,159 @@
+package interactor
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// BulkheadOption configures the Bulkhead middleware.
+type BulkheadOption func(*bulkheadConfig)
+
+type bulkheadConfig struct {
+	queueSize int
+	key       KeyFunc
+}
+
+// WithBulkheadQueue allows up to size runs to wait for a free slot instead of being rejected immediately.
+//
+// Waiting runs give up as soon as their context is done.
+func WithBulkheadQueue(size int) BulkheadOption {
+	return func(cfg *bulkheadConfig) {
+		cfg.queueSize = size
+	}
+}
+
+// WithBulkheadKey partitions the bulkhead by the given key.
+//
+// By default, each request type has its own compartment. Use it to put several request types into one group:
+//
+//	interactor.WithBulkheadKey(func(ctx context.Context, req interactor.Request) string {
+//		return "reports"
+//	})
+func WithBulkheadKey(key KeyFunc) BulkheadOption {
+	return func(cfg *bulkheadConfig) {
+		cfg.key = key
+	}
+}
+
+// Bulkhead limits the number of concurrent runs per compartment to maxConcurrent.
+//
+// When all the slots are taken and the waiting queue is full, it returns ErrBulkheadFull
+// without running the use case. A compartment is kept in memory only while it has runs in progress or waiting,
+// so keying it by tenant or user does not accumulate compartments.
+//
+// It panics if maxConcurrent is not positive.
+func Bulkhead(maxConcurrent int, opts ...BulkheadOption) Middleware {
+	if maxConcurrent <= 0 {
+		panic("interactor: non-positive maxConcurrent for Bulkhead")
+	}
+
+	cfg := &bulkheadConfig{key: RequestTypeKey}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	b := &bulkhead{
+		cfg:           cfg,
+		maxConcurrent: maxConcurrent,
+		compartments:  make(map[string]*compartment),
+	}
+
+	return func(next UseCaseRunnerFn) UseCaseRunnerFn {
+		return func(ctx context.Context, req Request, resp Response) error {
+			key := cfg.key(ctx, req)
+
+			release, err := b.acquire(ctx, key)
+			if err != nil {
+				return err
+			}
+			defer release()
+
+			return next(ctx, req, resp)
+		}
+	}
+}
+
+type compartment struct {
+	slots   chan struct{}
+	waiting int
+}
+
+type bulkhead struct {
+	cfg           *bulkheadConfig
+	maxConcurrent int
+
+	mu           sync.Mutex
+	compartments map[string]*compartment
+}
+
+func (b *bulkhead) acquire(ctx context.Context, key string) (func(), error) {
+	c, ok := b.tryAcquire(key)
+	if ok {
+		return func() { b.release(key, c) }, nil
+	}
+
+	if c == nil {
+		return nil, fmt.Errorf("%w: %s", ErrBulkheadFull, key)
+	}
+
+	defer b.stopWaiting(key, c)
+
+	select {
+	case c.slots <- struct{}{}:
+		return func() { b.release(key, c) }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// tryAcquire takes a free slot if there is one.
+//
+// Otherwise, it returns the compartment to wait on if the queue has room for one more, or nil if it does not.
+func (b *bulkhead) tryAcquire(key string) (*compartment, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c, ok := b.compartments[key]
+	if !ok {
+		c = &compartment{slots: make(chan struct{}, b.maxConcurrent)}
+		b.compartments[key] = c
+	}
+
+	select {
+	case c.slots <- struct{}{}:
+		return c, true
+	default:
+	}
+
+	if c.waiting >= b.cfg.queueSize {
+		return nil, false
+	}
+
+	c.waiting++
+
+	return c, false
+}
+
+func (b *bulkhead) release(key string, c *compartment) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	<-c.slots
+	b.dropIdle(key, c)
+}
+
+func (b *bulkhead) stopWaiting(key string, c *compartment) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c.waiting--
+	b.dropIdle(key, c)
+}
+
+// dropIdle forgets the compartment if no run holds or waits for its slots. It must be called with the mutex held.
+func (b *bulkhead) dropIdle(key string, c *compartment) {
+	if len(c.slots) == 0 && c.waiting == 0 && b.compartments[key] == c {
+		delete(b.compartments, key)
+	}
+}
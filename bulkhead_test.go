@@ -0,0 +1,183 @@
+package interactor_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/interactor/v2"
+)
+
+func TestBulkhead(t *testing.T) {
+	t.Parallel()
+
+	t.Run("when all the slots are taken, a run is rejected", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		useCase := NewBlockingUseCase()
+		defer useCase.Release()
+
+		runner := interactor.Chain(interactor.MustAdapt(useCase), interactor.Bulkhead(1))
+
+		go func() { _ = runner(context.Background(), TestRequest{}, &TestResponse{}) }()
+		useCase.Started(1)
+
+		// act
+		err := runner(context.Background(), TestRequest{}, &TestResponse{})
+
+		// assert
+		require.ErrorIs(t, err, interactor.ErrBulkheadFull)
+	})
+
+	t.Run("when a slot is released, the next run is admitted", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		runner := interactor.Chain(interactor.MustAdapt(ConcreteUseCase{}), interactor.Bulkhead(1))
+		require.NoError(t, runner(context.Background(), TestRequest{}, &TestResponse{}))
+
+		// act
+		var res TestResponse
+		err := runner(context.Background(), TestRequest{id: 123}, &res)
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, 123, res.result)
+	})
+
+	t.Run("each request type has its own compartment", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		useCase := NewBlockingUseCase()
+		defer useCase.Release()
+
+		runner := interactor.Chain(
+			func(ctx context.Context, req interactor.Request, resp interactor.Response) error {
+				if _, ok := req.(TestRequest); ok {
+					return interactor.MustAdapt(useCase)(ctx, req, resp)
+				}
+
+				return nil
+			},
+			interactor.Bulkhead(1),
+		)
+
+		go func() { _ = runner(context.Background(), TestRequest{}, &TestResponse{}) }()
+		useCase.Started(1)
+
+		// act
+		err := runner(context.Background(), &TestRequest{}, &TestResponse{})
+
+		// assert
+		require.NoError(t, err)
+	})
+
+	t.Run("request types can be grouped into a single compartment", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		useCase := NewBlockingUseCase()
+		defer useCase.Release()
+
+		group := func(context.Context, interactor.Request) string { return "reports" }
+		runner := interactor.Chain(
+			func(ctx context.Context, req interactor.Request, resp interactor.Response) error {
+				return interactor.MustAdapt(useCase)(ctx, req, resp)
+			},
+			interactor.Bulkhead(1, interactor.WithBulkheadKey(group)),
+		)
+
+		go func() { _ = runner(context.Background(), TestRequest{}, &TestResponse{}) }()
+		useCase.Started(1)
+
+		// act
+		err := runner(context.Background(), &TestRequest{}, &TestResponse{})
+
+		// assert
+		require.ErrorIs(t, err, interactor.ErrBulkheadFull)
+	})
+
+	t.Run("when the queue has room, a run waits for a free slot", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		useCase := NewBlockingUseCase()
+		runner := interactor.Chain(interactor.MustAdapt(useCase), interactor.Bulkhead(1, interactor.WithBulkheadQueue(1)))
+
+		go func() { _ = runner(context.Background(), TestRequest{}, &TestResponse{}) }()
+		useCase.Started(1)
+
+		errs := make(chan error, 1)
+		res := &TestResponse{}
+
+		go func() { errs <- runner(context.Background(), TestRequest{id: 123}, res) }()
+
+		// act
+		useCase.Release()
+
+		// assert
+		require.NoError(t, <-errs)
+		assert.Equal(t, 123, res.result)
+	})
+
+	t.Run("when the queue is full, a run is rejected", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		useCase := NewBlockingUseCase()
+		defer useCase.Release()
+
+		runner := interactor.Chain(interactor.MustAdapt(useCase), interactor.Bulkhead(1, interactor.WithBulkheadQueue(1)))
+
+		go func() { _ = runner(context.Background(), TestRequest{}, &TestResponse{}) }()
+		useCase.Started(1)
+
+		go func() { _ = runner(context.Background(), TestRequest{}, &TestResponse{}) }()
+
+		// act
+		rejected := func() bool {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+			defer cancel()
+
+			return errors.Is(runner(ctx, TestRequest{}, &TestResponse{}), interactor.ErrBulkheadFull)
+		}
+
+		// assert
+		assert.Eventually(t, rejected, time.Second, time.Millisecond)
+	})
+
+	t.Run("a waiting run gives up when its context is done", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		useCase := NewBlockingUseCase()
+		defer useCase.Release()
+
+		runner := interactor.Chain(interactor.MustAdapt(useCase), interactor.Bulkhead(1, interactor.WithBulkheadQueue(1)))
+
+		go func() { _ = runner(context.Background(), TestRequest{}, &TestResponse{}) }()
+		useCase.Started(1)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		// act
+		err := runner(ctx, TestRequest{}, &TestResponse{})
+
+		// assert
+		require.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("the number of concurrent runs must be positive", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Panics(t, func() { interactor.Bulkhead(0) })
+		assert.Panics(t, func() { interactor.Bulkhead(-1) })
+	})
+}
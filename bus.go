@@ -0,0 +1,151 @@
+package interactor
+
+import (
+	"context"
+	"fmt"
+)
+
+// Command is a request which changes the state of the system and produces no response.
+//
+// Embed CommandMarker to implement it:
+//
+//	type PlaceOrder struct {
+//		interactor.CommandMarker
+//		OrderID int
+//	}
+type Command interface {
+	IsCommand()
+}
+
+// Query is a request which reads the state of the system and does not change it.
+//
+// Embed QueryMarker to implement it.
+type Query interface {
+	IsQuery()
+}
+
+// CommandMarker marks a request as a Command.
+type CommandMarker struct{}
+
+// IsCommand implements Command interface.
+func (CommandMarker) IsCommand() {}
+
+// QueryMarker marks a request as a Query.
+type QueryMarker struct{}
+
+// IsQuery implements Query interface.
+func (QueryMarker) IsQuery() {}
+
+// NoResponse is the response command handlers are run with.
+//
+// A command handler adapted with Func or Adapt must accept *NoResponse.
+type NoResponse struct{}
+
+// BusOption configures a CommandBus or a QueryBus.
+type BusOption func(*busConfig)
+
+type busConfig struct {
+	middlewares []Middleware
+}
+
+// WithBusMiddleware adds the given middlewares to every request run by the bus.
+//
+// They are applied in the given order, the first one being the outermost, and wrap the default middleware of the bus,
+// so that e.g. authorization is checked before a transaction is started or a cached result is returned.
+func WithBusMiddleware(middlewares ...Middleware) BusOption {
+	return func(cfg *busConfig) {
+		cfg.middlewares = append(cfg.middlewares, middlewares...)
+	}
+}
+
+// CommandBus runs commands.
+type CommandBus struct {
+	dispatcher *Dispatcher
+}
+
+// NewCommandBus creates a new CommandBus which runs every command in a database transaction started on the given db.
+//
+// If db is nil, commands are run without transactions.
+func NewCommandBus(db TxBeginner, opts ...BusOption) *CommandBus {
+	cfg := newBusConfig(opts...)
+
+	middlewares := cfg.middlewares
+	if db != nil {
+		middlewares = appendCopy(middlewares, Transactional(db, nil))
+	}
+
+	return &CommandBus{dispatcher: newBusDispatcher(middlewares)}
+}
+
+// Register registers the given UseCaseRunner for the provided command type.
+//
+// It returns ErrNotACommand if the command is a Query as well.
+func (b *CommandBus) Register(cmd Command, runner UseCaseRunnerFn) error {
+	if _, ok := cmd.(Query); ok {
+		return fmt.Errorf("%w: %T is a query", ErrNotACommand, cmd)
+	}
+
+	b.dispatcher.Register(cmd, runner)
+
+	return nil
+}
+
+// Run runs the command.
+//
+// The use case is given *NoResponse, as commands produce no response.
+func (b *CommandBus) Run(ctx context.Context, cmd Command) error {
+	return b.dispatcher.Run(ctx, cmd, &NoResponse{})
+}
+
+// QueryBus runs queries.
+type QueryBus struct {
+	dispatcher *Dispatcher
+}
+
+// NewQueryBus creates a new QueryBus which caches the query results in the given cache.
+//
+// If cache is nil, results are not cached.
+func NewQueryBus(cache *Cache, opts ...BusOption) *QueryBus {
+	cfg := newBusConfig(opts...)
+
+	middlewares := cfg.middlewares
+	if cache != nil {
+		middlewares = appendCopy(middlewares, cache.Middleware())
+	}
+
+	return &QueryBus{dispatcher: newBusDispatcher(middlewares)}
+}
+
+// Register registers the given UseCaseRunner for the provided query type.
+//
+// It returns ErrNotAQuery if the query is a Command as well.
+func (b *QueryBus) Register(query Query, runner UseCaseRunnerFn) error {
+	if _, ok := query.(Command); ok {
+		return fmt.Errorf("%w: %T is a command", ErrNotAQuery, query)
+	}
+
+	b.dispatcher.Register(query, runner)
+
+	return nil
+}
+
+// Run runs the query and writes the result to the provided Response.
+func (b *QueryBus) Run(ctx context.Context, query Query, resp Response) error {
+	return b.dispatcher.Run(ctx, query, resp)
+}
+
+func newBusConfig(opts ...BusOption) *busConfig {
+	cfg := &busConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return cfg
+}
+
+func newBusDispatcher(middlewares []Middleware) *Dispatcher {
+	d := NewDispatcher()
+	d.Use(middlewares...)
+
+	return d
+}
@@ -0,0 +1,197 @@
+package interactor_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/interactor/v2"
+)
+
+type RenameUser struct {
+	interactor.CommandMarker
+	UserID string
+	Name   string
+}
+
+type FindUser struct {
+	interactor.QueryMarker
+	UserID string
+}
+
+func (FindUser) CacheTTL() time.Duration {
+	return time.Minute
+}
+
+type FindUserResponse struct {
+	Name string
+}
+
+type AmbiguousRequest struct {
+	interactor.CommandMarker
+	interactor.QueryMarker
+}
+
+func TestCommandBus(t *testing.T) {
+	t.Parallel()
+
+	t.Run("a command is run in a transaction", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		fake := &FakeDB{}
+
+		var inTx bool
+
+		bus := interactor.NewCommandBus(fake.Open())
+		err := bus.Register(RenameUser{}, interactor.Must(interactor.Func(
+			func(ctx context.Context, _ RenameUser, _ *interactor.NoResponse) error {
+				_, inTx = interactor.TxFromContext(ctx)
+
+				return nil
+			},
+		)))
+		require.NoError(t, err)
+
+		// act
+		err = bus.Run(context.Background(), RenameUser{UserID: "123", Name: "John"})
+
+		// assert
+		require.NoError(t, err)
+		assert.True(t, inTx)
+		assertTransactions(t, fake, 1, 1, 0)
+	})
+
+	t.Run("a failed command rolls the transaction back", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		fake := &FakeDB{}
+
+		bus := interactor.NewCommandBus(fake.Open())
+		require.NoError(t, bus.Register(RenameUser{}, failingRunner(errSomeErr)))
+
+		// act
+		err := bus.Run(context.Background(), RenameUser{})
+
+		// assert
+		require.ErrorIs(t, err, errSomeErr)
+		assertTransactions(t, fake, 1, 0, 1)
+	})
+
+	t.Run("a command handler expecting a response is rejected", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		bus := interactor.NewCommandBus(nil)
+		err := bus.Register(RenameUser{}, interactor.Must(interactor.Func(
+			func(context.Context, RenameUser, *FindUserResponse) error {
+				return nil
+			},
+		)))
+		require.NoError(t, err)
+
+		// act
+		err = bus.Run(context.Background(), RenameUser{})
+
+		// assert
+		require.ErrorIs(t, err, interactor.ErrResultTypeMismatch)
+	})
+
+	t.Run("a query cannot be registered", func(t *testing.T) {
+		t.Parallel()
+
+		err := interactor.NewCommandBus(nil).Register(AmbiguousRequest{}, succeedingRunner)
+		require.ErrorIs(t, err, interactor.ErrNotACommand)
+	})
+
+	t.Run("the given middlewares wrap the transaction", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		fake := &FakeDB{}
+		authorizer := interactor.NewAuthorizer(interactor.WithStrictAuthorization())
+
+		bus := interactor.NewCommandBus(fake.Open(), interactor.WithBusMiddleware(authorizer.Middleware()))
+		require.NoError(t, bus.Register(RenameUser{}, succeedingRunner))
+
+		// act
+		err := bus.Run(context.Background(), RenameUser{})
+
+		// assert
+		require.ErrorIs(t, err, interactor.ErrForbidden)
+		assertTransactions(t, fake, 0, 0, 0)
+	})
+}
+
+func TestQueryBus(t *testing.T) {
+	t.Parallel()
+
+	t.Run("a query result is cached", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		var runs int
+
+		bus := interactor.NewQueryBus(interactor.NewCache())
+		err := bus.Register(FindUser{}, interactor.Must(interactor.Func(
+			func(_ context.Context, req FindUser, res *FindUserResponse) error {
+				runs++
+				res.Name = "user " + req.UserID
+
+				return nil
+			},
+		)))
+		require.NoError(t, err)
+
+		require.NoError(t, bus.Run(context.Background(), FindUser{UserID: "123"}, &FindUserResponse{}))
+
+		// act
+		var res FindUserResponse
+		err = bus.Run(context.Background(), FindUser{UserID: "123"}, &res)
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, 1, runs)
+		assert.Equal(t, "user 123", res.Name)
+	})
+
+	t.Run("a query is not cached without a cache", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		var runs int
+
+		bus := interactor.NewQueryBus(nil)
+		require.NoError(t, bus.Register(FindUser{}, func(context.Context, interactor.Request, interactor.Response) error {
+			runs++
+
+			return nil
+		}))
+
+		// act
+		for i := 0; i < 2; i++ {
+			require.NoError(t, bus.Run(context.Background(), FindUser{}, &FindUserResponse{}))
+		}
+
+		// assert
+		assert.Equal(t, 2, runs)
+	})
+
+	t.Run("a command cannot be registered", func(t *testing.T) {
+		t.Parallel()
+
+		err := interactor.NewQueryBus(nil).Register(AmbiguousRequest{}, succeedingRunner)
+		require.ErrorIs(t, err, interactor.ErrNotAQuery)
+	})
+
+	t.Run("an unregistered query is reported", func(t *testing.T) {
+		t.Parallel()
+
+		err := interactor.NewQueryBus(nil).Run(context.Background(), FindUser{}, &FindUserResponse{})
+		require.ErrorIs(t, err, interactor.ErrUseCaseRunnerNotFound)
+	})
+}
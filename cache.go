@@ -0,0 +1,217 @@
+package interactor
+
+import (
+	"container/list"
+	"context"
+	"reflect"
+	"sync"
+	"time"
+)
+
+const defaultCacheSize = 1000
+
+// Cacheable is implemented by requests whose responses may be cached.
+//
+// Only the requests implementing it are cached, the rest are passed through.
+type Cacheable interface {
+	// CacheTTL returns how long the response stays fresh.
+	//
+	// Zero means the cache default TTL.
+	CacheTTL() time.Duration
+}
+
+// CacheOption configures a Cache.
+type CacheOption func(*Cache)
+
+// WithCacheTTL sets the default TTL of cached responses.
+//
+// By default, cached responses never expire and are only evicted when the cache is full.
+func WithCacheTTL(ttl time.Duration) CacheOption {
+	return func(c *Cache) {
+		c.ttl = ttl
+	}
+}
+
+// WithCacheSize sets the maximum number of cached responses.
+//
+// When the cache is full, the least recently used response is evicted.
+func WithCacheSize(size int) CacheOption {
+	return func(c *Cache) {
+		c.size = size
+	}
+}
+
+// WithCacheClock sets the function used to get the current time.
+func WithCacheClock(now func() time.Time) CacheOption {
+	return func(c *Cache) {
+		c.now = now
+	}
+}
+
+// Cache stores responses of Cacheable requests.
+//
+// The responses are keyed on a canonical hash of the request value, so equal requests share the cached response.
+type Cache struct {
+	ttl  time.Duration
+	size int
+	now  func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+}
+
+type cacheEntry struct {
+	key       string
+	reqType   reflect.Type
+	resp      Response
+	expiresAt time.Time
+}
+
+// NewCache creates a new Cache instance.
+func NewCache(opts ...CacheOption) *Cache {
+	c := &Cache{
+		size:    defaultCacheSize,
+		now:     time.Now,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Middleware returns a middleware which serves Cacheable requests from the cache.
+//
+// On a hit, the use case is not run and a copy of the cached response is written to the provided response.
+// On a miss, a copy of the response is cached if the use case succeeds. Errors are never cached.
+func (c *Cache) Middleware() Middleware {
+	return func(next UseCaseRunnerFn) UseCaseRunnerFn {
+		return func(ctx context.Context, req Request, resp Response) error {
+			cacheable, ok := req.(Cacheable)
+			if !ok {
+				return next(ctx, req, resp)
+			}
+
+			key, err := cacheKey(req, resp)
+			if err != nil {
+				return next(ctx, req, resp)
+			}
+
+			if cached, ok := c.get(key); ok {
+				return copyResponse(resp, cached)
+			}
+
+			if err := next(ctx, req, resp); err != nil {
+				return err
+			}
+
+			c.put(key, req, resp, cacheable.CacheTTL())
+
+			return nil
+		}
+	}
+}
+
+// Invalidate removes all the cached responses for the type of the given request.
+func (c *Cache) Invalidate(req Request) {
+	reqType := reflect.TypeOf(req)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for el := c.lru.Front(); el != nil; {
+		next := el.Next()
+		if el.Value.(*cacheEntry).reqType == reqType {
+			c.remove(el)
+		}
+		el = next
+	}
+}
+
+// Purge removes all the cached responses.
+func (c *Cache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[string]*list.Element)
+	c.lru.Init()
+}
+
+// Len returns the number of cached responses.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.lru.Len()
+}
+
+func (c *Cache) get(key string) (Response, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := el.Value.(*cacheEntry)
+	if !entry.expiresAt.IsZero() && !c.now().Before(entry.expiresAt) {
+		c.remove(el)
+
+		return nil, false
+	}
+
+	c.lru.MoveToFront(el)
+
+	return entry.resp, true
+}
+
+func (c *Cache) put(key string, req Request, resp Response, ttl time.Duration) {
+	cached, err := newResponseLike(resp)
+	if err != nil {
+		return
+	}
+
+	if err := copyResponse(cached, resp); err != nil {
+		return
+	}
+
+	if ttl == 0 {
+		ttl = c.ttl
+	}
+
+	entry := &cacheEntry{key: key, reqType: reflect.TypeOf(req), resp: cached}
+	if ttl > 0 {
+		entry.expiresAt = c.now().Add(ttl)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		c.remove(el)
+	}
+
+	c.entries[key] = c.lru.PushFront(entry)
+
+	for c.lru.Len() > c.size {
+		c.remove(c.lru.Back())
+	}
+}
+
+func (c *Cache) remove(el *list.Element) {
+	c.lru.Remove(el)
+	delete(c.entries, el.Value.(*cacheEntry).key)
+}
+
+func cacheKey(req Request, resp Response) (string, error) {
+	hash, err := hashValue(req)
+	if err != nil {
+		return "", err
+	}
+
+	return reflect.TypeOf(resp).String() + ":" + hash, nil
+}
@@ -0,0 +1,261 @@
+package interactor_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/interactor/v2"
+)
+
+func TestCache(t *testing.T) {
+	t.Parallel()
+
+	t.Run("on a hit the use case is skipped and the cached response is copied", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		useCase := &CountingUseCase{}
+		runner := interactor.Chain(interactor.MustAdapt(useCase), interactor.NewCache().Middleware())
+
+		req := CacheableRequest{id: 123, tags: []string{"a"}, filter: map[string]int{"x": 1, "y": 2}}
+		require.NoError(t, runner(context.Background(), req, &DetailedResponse{}))
+
+		// act
+		var res DetailedResponse
+		err := runner(context.Background(), req, &res)
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, 1, useCase.Runs())
+		assert.Equal(t, DetailedResponse{result: 123, tags: []string{"a"}, owner: &Owner{Name: "owner"}}, res)
+	})
+
+	t.Run("equal requests share the cached response", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		useCase := &CountingUseCase{}
+		runner := interactor.Chain(interactor.MustAdapt(useCase), interactor.NewCache().Middleware())
+
+		first := CacheableRequest{id: 123, tags: []string{"a"}, filter: map[string]int{"x": 1, "y": 2}}
+		second := CacheableRequest{id: 123, tags: []string{"a"}, filter: map[string]int{"y": 2, "x": 1}}
+		require.NoError(t, runner(context.Background(), first, &DetailedResponse{}))
+
+		// act
+		err := runner(context.Background(), second, &DetailedResponse{})
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, 1, useCase.Runs())
+	})
+
+	t.Run("different requests are cached separately", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		useCase := &CountingUseCase{}
+		runner := interactor.Chain(interactor.MustAdapt(useCase), interactor.NewCache().Middleware())
+		require.NoError(t, runner(context.Background(), CacheableRequest{id: 1}, &DetailedResponse{}))
+
+		// act
+		var res DetailedResponse
+		err := runner(context.Background(), CacheableRequest{id: 2}, &res)
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, 2, useCase.Runs())
+		assert.Equal(t, 2, res.result)
+	})
+
+	t.Run("the cached response is a deep copy", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		runner := interactor.Chain(interactor.MustAdapt(&CountingUseCase{}), interactor.NewCache().Middleware())
+		req := CacheableRequest{id: 123, tags: []string{"a"}}
+
+		var first DetailedResponse
+		require.NoError(t, runner(context.Background(), req, &first))
+
+		first.tags[0] = "changed"
+		first.owner.Name = "changed"
+
+		// act
+		var second DetailedResponse
+		err := runner(context.Background(), req, &second)
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a"}, second.tags)
+		assert.Equal(t, "owner", second.owner.Name)
+	})
+
+	t.Run("values which cannot be addressed are deep copied", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		when := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+		want := OpaqueResponse{
+			Value:  when,
+			Times:  map[string]time.Time{"created": when},
+			Nested: []interface{}{[1]time.Time{when}},
+		}
+
+		runner := interactor.Chain(func(_ context.Context, _ interactor.Request, resp interactor.Response) error {
+			*resp.(*OpaqueResponse) = want
+
+			return nil
+		}, interactor.NewCache().Middleware())
+		require.NoError(t, runner(context.Background(), CacheableRequest{}, &OpaqueResponse{}))
+
+		// act
+		var res OpaqueResponse
+		err := runner(context.Background(), CacheableRequest{}, &res)
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, want, res)
+	})
+
+	t.Run("errors are not cached", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		useCase := &CountingUseCase{err: errSomeErr}
+		cache := interactor.NewCache()
+		runner := interactor.Chain(interactor.MustAdapt(useCase), cache.Middleware())
+		require.ErrorIs(t, runner(context.Background(), CacheableRequest{}, &DetailedResponse{}), errSomeErr)
+
+		// act
+		err := runner(context.Background(), CacheableRequest{}, &DetailedResponse{})
+
+		// assert
+		require.ErrorIs(t, err, errSomeErr)
+		assert.Equal(t, 2, useCase.Runs())
+		assert.Zero(t, cache.Len())
+	})
+
+	t.Run("requests which are not cacheable are passed through", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		cache := interactor.NewCache()
+		runner := interactor.Chain(interactor.MustAdapt(ConcreteUseCase{}), cache.Middleware())
+
+		// act
+		var res TestResponse
+		err := runner(context.Background(), TestRequest{id: 123}, &res)
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, 123, res.result)
+		assert.Zero(t, cache.Len())
+	})
+
+	t.Run("cached responses expire after the default TTL", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		clock := NewFakeClock()
+		useCase := &CountingUseCase{}
+		cache := interactor.NewCache(interactor.WithCacheTTL(time.Minute), interactor.WithCacheClock(clock.Now))
+		runner := interactor.Chain(interactor.MustAdapt(useCase), cache.Middleware())
+		require.NoError(t, runner(context.Background(), CacheableRequest{}, &DetailedResponse{}))
+
+		// act
+		clock.Advance(time.Minute)
+		err := runner(context.Background(), CacheableRequest{}, &DetailedResponse{})
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, 2, useCase.Runs())
+	})
+
+	t.Run("the request TTL overrides the default one", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		clock := NewFakeClock()
+		useCase := &CountingUseCase{}
+		cache := interactor.NewCache(interactor.WithCacheTTL(time.Minute), interactor.WithCacheClock(clock.Now))
+		runner := interactor.Chain(interactor.MustAdapt(useCase), cache.Middleware())
+
+		req := CacheableRequest{ttl: time.Hour}
+		require.NoError(t, runner(context.Background(), req, &DetailedResponse{}))
+
+		// act
+		clock.Advance(time.Minute)
+		err := runner(context.Background(), req, &DetailedResponse{})
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, 1, useCase.Runs())
+	})
+
+	t.Run("the least recently used response is evicted when the cache is full", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		useCase := &CountingUseCase{}
+		cache := interactor.NewCache(interactor.WithCacheSize(2))
+		runner := interactor.Chain(interactor.MustAdapt(useCase), cache.Middleware())
+
+		require.NoError(t, runner(context.Background(), CacheableRequest{id: 1}, &DetailedResponse{}))
+		require.NoError(t, runner(context.Background(), CacheableRequest{id: 2}, &DetailedResponse{}))
+		require.NoError(t, runner(context.Background(), CacheableRequest{id: 1}, &DetailedResponse{}))
+		require.NoError(t, runner(context.Background(), CacheableRequest{id: 3}, &DetailedResponse{}))
+
+		// act
+		err1 := runner(context.Background(), CacheableRequest{id: 1}, &DetailedResponse{})
+		err2 := runner(context.Background(), CacheableRequest{id: 2}, &DetailedResponse{})
+
+		// assert
+		require.NoError(t, err1)
+		require.NoError(t, err2)
+		assert.Equal(t, 4, useCase.Runs())
+		assert.Equal(t, 2, cache.Len())
+	})
+
+	t.Run("cached responses can be invalidated by request type", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		useCase := &CountingUseCase{}
+		cache := interactor.NewCache()
+		runner := interactor.Chain(interactor.MustAdapt(useCase), cache.Middleware())
+
+		require.NoError(t, runner(context.Background(), CacheableRequest{id: 1}, &DetailedResponse{}))
+		require.NoError(t, runner(context.Background(), CacheableRequest{id: 2}, &DetailedResponse{}))
+
+		// act
+		cache.Invalidate(CacheableRequest{})
+
+		// assert
+		assert.Zero(t, cache.Len())
+	})
+
+	t.Run("cached responses can be purged", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		cache := interactor.NewCache()
+		runner := interactor.Chain(interactor.MustAdapt(&CountingUseCase{}), cache.Middleware())
+		require.NoError(t, runner(context.Background(), CacheableRequest{id: 1}, &DetailedResponse{}))
+
+		// act
+		cache.Purge()
+
+		// assert
+		assert.Zero(t, cache.Len())
+	})
+}
+
+type OpaqueResponse struct {
+	Value  interface{}
+	Times  map[string]time.Time
+	Nested []interface{}
+}
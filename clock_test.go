@@ -0,0 +1,40 @@
+package interactor_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/screwyprof/interactor/v2"
+)
+
+func TestSystemClock(t *testing.T) {
+	t.Parallel()
+
+	t.Run("the timer fires once it expires", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		before := interactor.SystemClock.Now()
+
+		// act
+		fired := <-interactor.SystemClock.NewTimer(tick).C()
+
+		// assert
+		assert.False(t, fired.Before(before.Add(tick)))
+	})
+
+	t.Run("a stopped timer does not fire", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		timer := interactor.SystemClock.NewTimer(time.Hour)
+
+		// act
+		stopped := timer.Stop()
+
+		// assert
+		assert.True(t, stopped)
+	})
+}
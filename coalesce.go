@@ -0,0 +1,169 @@
+package interactor
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Coalesce returns a middleware which lets concurrent runs of equal requests share a single execution.
+//
+// Only the requests which do not change the state of the system are coalesced, that is the ones implementing
+// Query or Cacheable. The rest, e.g. commands, are passed through, as each of their runs has an effect of its own.
+// Requests are equal if their canonical hashes are, see Cache. The first run becomes the leader and
+// runs the use case, while the rest wait for it. Each caller gets its own copy of the response.
+//
+// The shared execution is not bound to the leader's context: when a caller's context is done,
+// that caller stops waiting and gets the context error, while the execution carries on for the rest.
+// The execution's context is cancelled only when all the callers have given up.
+// Context values of the leader are visible to the use case. If the use case panics, the panic is re-raised
+// in every waiting caller.
+func Coalesce() Middleware {
+	g := &flightGroup{flights: make(map[string]*flight)}
+
+	return func(next UseCaseRunnerFn) UseCaseRunnerFn {
+		return func(ctx context.Context, req Request, resp Response) error {
+			if !coalescable(req) {
+				return next(ctx, req, resp)
+			}
+
+			key, err := cacheKey(req, resp)
+			if err != nil {
+				return next(ctx, req, resp)
+			}
+
+			f, err := g.join(ctx, key, next, req, resp)
+			if err != nil {
+				return err
+			}
+
+			return g.wait(ctx, key, f, resp)
+		}
+	}
+}
+
+func coalescable(req Request) bool {
+	switch req.(type) {
+	case Query, Cacheable:
+		return true
+	default:
+		return false
+	}
+}
+
+type flight struct {
+	done    chan struct{}
+	cancel  context.CancelFunc
+	waiters int
+
+	resp     Response
+	err      error
+	panicked bool
+	panicVal interface{}
+}
+
+type flightGroup struct {
+	mu      sync.Mutex
+	flights map[string]*flight
+}
+
+func (g *flightGroup) join(ctx context.Context, key string, next UseCaseRunnerFn, req Request, resp Response) (*flight, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if f, ok := g.flights[key]; ok {
+		f.waiters++
+
+		return f, nil
+	}
+
+	shared, err := newResponseLike(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	execCtx, cancel := context.WithCancel(detach(ctx))
+	f := &flight{done: make(chan struct{}), cancel: cancel, waiters: 1, resp: shared}
+	g.flights[key] = f
+
+	go g.run(execCtx, key, f, next, req)
+
+	return f, nil
+}
+
+// run runs the shared execution. A panic is recovered and handed over to the callers.
+func (g *flightGroup) run(ctx context.Context, key string, f *flight, next UseCaseRunnerFn, req Request) {
+	defer close(f.done)
+	defer f.cancel()
+	defer g.forget(key, f)
+	defer func() {
+		if r := recover(); r != nil {
+			f.panicked, f.panicVal = true, r
+		}
+	}()
+
+	f.err = next(ctx, req, f.resp)
+}
+
+func (g *flightGroup) forget(key string, f *flight) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.flights[key] == f {
+		delete(g.flights, key)
+	}
+}
+
+func (g *flightGroup) wait(ctx context.Context, key string, f *flight, resp Response) error {
+	select {
+	case <-f.done:
+		if f.panicked {
+			panic(f.panicVal)
+		}
+
+		if f.err != nil {
+			return f.err
+		}
+
+		return copyResponse(resp, f.resp)
+	case <-ctx.Done():
+		g.leave(key, f)
+
+		return ctx.Err()
+	}
+}
+
+func (g *flightGroup) leave(key string, f *flight) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	f.waiters--
+	if f.waiters > 0 {
+		return
+	}
+
+	// Nobody waits for the result anymore, so the next caller should start afresh.
+	if g.flights[key] == f {
+		delete(g.flights, key)
+	}
+
+	f.cancel()
+}
+
+// detachedContext carries the values of its parent, but not its deadline nor cancellation.
+type detachedContext struct {
+	value func(key interface{}) interface{}
+}
+
+// detach returns a context which is never cancelled but keeps the values of the given one.
+func detach(ctx context.Context) context.Context {
+	return detachedContext{value: ctx.Value}
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
+
+func (c detachedContext) Value(key interface{}) interface{} {
+	return c.value(key)
+}
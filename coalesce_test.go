@@ -0,0 +1,272 @@
+package interactor_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/interactor/v2"
+)
+
+func TestCoalesce(t *testing.T) {
+	t.Parallel()
+
+	t.Run("concurrent runs of equal requests share a single execution", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		useCase := NewBlockingUseCase()
+		executions := &CountingMiddleware{}
+		runner := interactor.Chain(lookingUp(useCase), interactor.Coalesce(), executions.Middleware())
+		ctx := &WaitingContext{}
+
+		responses := []*TestResponse{{}, {}, {}}
+		errs := make([]error, len(responses))
+
+		var wg sync.WaitGroup
+		for i := range responses {
+			i := i
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs[i] = runner(ctx, Lookup{id: 123}, responses[i])
+			}()
+		}
+
+		useCase.Started(1)
+		ctx.Waiting(t, len(responses))
+
+		// act
+		useCase.Release()
+		wg.Wait()
+
+		// assert
+		assert.Equal(t, 1, executions.Count())
+		for i, res := range responses {
+			require.NoError(t, errs[i])
+			assert.Equal(t, &TestResponse{result: 123}, res)
+		}
+	})
+
+	t.Run("concurrent runs of equal commands are executed separately", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		useCase := NewBlockingUseCase()
+		executions := &CountingMiddleware{}
+		runner := interactor.Chain(interactor.MustAdapt(useCase), interactor.Coalesce(), executions.Middleware())
+
+		errs := make(chan error, 2)
+		for i := 0; i < cap(errs); i++ {
+			go func() { errs <- runner(context.Background(), TestRequest{id: 123}, &TestResponse{}) }()
+		}
+
+		// act
+		useCase.Started(cap(errs))
+		useCase.Release()
+
+		// assert
+		require.NoError(t, <-errs)
+		require.NoError(t, <-errs)
+		assert.Equal(t, 2, executions.Count())
+	})
+
+	t.Run("different requests are executed separately", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		executions := &CountingMiddleware{}
+		runner := interactor.Chain(lookingUp(ConcreteUseCase{}), interactor.Coalesce(), executions.Middleware())
+
+		// act
+		var first, second TestResponse
+		err1 := runner(context.Background(), Lookup{id: 1}, &first)
+		err2 := runner(context.Background(), Lookup{id: 2}, &second)
+
+		// assert
+		require.NoError(t, err1)
+		require.NoError(t, err2)
+		assert.Equal(t, 2, executions.Count())
+		assert.Equal(t, 1, first.result)
+		assert.Equal(t, 2, second.result)
+	})
+
+	t.Run("the shared error is returned to every caller", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		runner := interactor.Chain(lookingUp(ConcreteUseCase{err: errSomeErr}), interactor.Coalesce())
+
+		// act
+		err := runner(context.Background(), Lookup{}, &TestResponse{})
+
+		// assert
+		require.ErrorIs(t, err, errSomeErr)
+	})
+
+	t.Run("a panic of the shared execution is re-raised in every caller", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		release := make(chan struct{})
+		runner := interactor.Chain(func(context.Context, interactor.Request, interactor.Response) error {
+			<-release
+			panic("boom")
+		}, interactor.Coalesce())
+
+		ctx := &WaitingContext{}
+		panics := make(chan interface{}, 2)
+
+		for i := 0; i < cap(panics); i++ {
+			go func() {
+				defer func() { panics <- recover() }()
+
+				_ = runner(ctx, Lookup{}, &TestResponse{})
+			}()
+		}
+
+		ctx.Waiting(t, cap(panics))
+
+		// act
+		close(release)
+
+		// assert
+		assert.Equal(t, "boom", <-panics)
+		assert.Equal(t, "boom", <-panics)
+	})
+
+	t.Run("when the leader's context is cancelled, the execution carries on for the followers", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		useCase := NewBlockingUseCase()
+		runner := interactor.Chain(lookingUp(useCase), interactor.Coalesce())
+
+		leaderCtx, cancelLeader := context.WithCancel(context.Background())
+		followerCtx := &WaitingContext{}
+		leaderErr, followerErr := make(chan error, 1), make(chan error, 1)
+		follower := &TestResponse{}
+
+		go func() { leaderErr <- runner(leaderCtx, Lookup{id: 123}, &TestResponse{}) }()
+		useCase.Started(1)
+		go func() { followerErr <- runner(followerCtx, Lookup{id: 123}, follower) }()
+
+		followerCtx.Waiting(t, 1)
+
+		// act
+		cancelLeader()
+		require.ErrorIs(t, <-leaderErr, context.Canceled)
+		useCase.Release()
+
+		// assert
+		require.NoError(t, <-followerErr)
+		assert.Equal(t, &TestResponse{result: 123}, follower)
+	})
+
+	t.Run("when every caller gives up, the execution is cancelled", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		useCase := NewBlockingUseCase()
+		defer useCase.Release()
+
+		executions := &CountingMiddleware{}
+		runner := interactor.Chain(lookingUp(useCase), interactor.Coalesce(), executions.Middleware())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		errs := make(chan error, 1)
+
+		go func() { errs <- runner(ctx, Lookup{}, &TestResponse{}) }()
+		useCase.Started(1)
+
+		// act
+		cancel()
+
+		// assert
+		require.ErrorIs(t, <-errs, context.Canceled)
+		assert.Eventually(t, func() bool { return len(executions.Errors()) == 1 }, time.Second, time.Millisecond)
+		require.ErrorIs(t, executions.Errors()[0], context.Canceled)
+	})
+
+	t.Run("context values of the caller are visible to the use case", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		var tenant interface{}
+		runner := interactor.Chain(
+			func(ctx context.Context, req interactor.Request, resp interactor.Response) error {
+				tenant = ctx.Value(tenantKey{})
+
+				return nil
+			},
+			interactor.Coalesce(),
+		)
+
+		ctx := context.WithValue(context.Background(), tenantKey{}, "acme")
+
+		// act
+		err := runner(ctx, Lookup{}, &TestResponse{})
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, "acme", tenant)
+	})
+}
+
+// Lookup is a query, so its concurrent runs are coalesced.
+type Lookup struct {
+	interactor.QueryMarker
+	id int
+}
+
+// lookingUp runs the use case with a TestRequest carrying the ID of the Lookup.
+func lookingUp(useCase interface {
+	Run(ctx context.Context, req TestRequest, res *TestResponse) error
+},
+) interactor.UseCaseRunnerFn {
+	return func(ctx context.Context, req interactor.Request, resp interactor.Response) error {
+		lookup, _ := req.(Lookup)
+		res, _ := resp.(*TestResponse)
+
+		return useCase.Run(ctx, TestRequest{id: lookup.id}, res)
+	}
+}
+
+// WaitingContext is a context which is never cancelled and counts the callers waiting on its Done channel.
+//
+// Coalesce waits on the caller's context only once the caller has joined a flight,
+// so it tells how many callers have joined.
+type WaitingContext struct {
+	mu      sync.Mutex
+	waiting int
+}
+
+func (c *WaitingContext) Deadline() (time.Time, bool)   { return time.Time{}, false }
+func (c *WaitingContext) Err() error                    { return nil }
+func (c *WaitingContext) Value(interface{}) interface{} { return nil }
+
+func (c *WaitingContext) Done() <-chan struct{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.waiting++
+
+	return nil
+}
+
+// Waiting waits for n callers to wait on the context.
+func (c *WaitingContext) Waiting(t *testing.T, n int) {
+	t.Helper()
+
+	require.Eventually(t, func() bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		return c.waiting >= n
+	}, time.Second, time.Millisecond)
+}
@@ -0,0 +1,410 @@
+package interactor
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// Container constructs use cases by resolving their dependencies from the registered providers.
+//
+// Every provided type is built once, the first time it is needed, and shared by all its dependants.
+//
+//	container := interactor.NewContainer()
+//	_ = container.ProvideValue(db)
+//	_ = container.Provide(NewOrderRepository) // func NewOrderRepository(db *sql.DB) OrderRepository
+//
+//	container.Register(PlaceOrder{}, &PlaceOrderUseCase{}) // type PlaceOrderUseCase struct{ Orders OrderRepository }
+//	container.Register(CancelOrder{}, NewCancelOrderUseCase)
+//
+//	if err := container.Wire(dispatcher); err != nil {
+//		log.Fatal(err)
+//	}
+type Container struct {
+	mu        sync.Mutex
+	providers map[reflect.Type]*provider
+	useCases  []containerUseCase
+}
+
+type provider struct {
+	ctor     reflect.Value
+	deps     []reflect.Type
+	value    reflect.Value
+	resolved bool
+}
+
+type containerUseCase struct {
+	request Request
+	useCase interface{}
+}
+
+// NewContainer creates a new Container instance.
+func NewContainer() *Container {
+	return &Container{providers: make(map[reflect.Type]*provider)}
+}
+
+// Provide registers a constructor for the type it returns.
+//
+// The constructor is a function returning the value and optionally an error. Its parameters are resolved
+// from the container:
+//
+//	func NewOrderRepository(db *sql.DB) (OrderRepository, error)
+//
+// It returns ErrInvalidProvider if the constructor has another signature and ErrDuplicateProvider
+// if the type is already provided.
+func (c *Container) Provide(constructor interface{}) error {
+	ctor := reflect.ValueOf(constructor)
+	if !isConstructor(ctor) {
+		return fmt.Errorf("%w: %T", ErrInvalidProvider, constructor)
+	}
+
+	deps := make([]reflect.Type, ctor.Type().NumIn())
+	for i := range deps {
+		deps[i] = ctor.Type().In(i)
+	}
+
+	return c.add(ctor.Type().Out(0), &provider{ctor: ctor, deps: deps})
+}
+
+// ProvideValue registers the given value for its type.
+//
+// To provide a value for an interface, use Provide with a constructor returning the interface.
+func (c *Container) ProvideValue(value interface{}) error {
+	if value == nil {
+		return fmt.Errorf("%w: nil value", ErrInvalidProvider)
+	}
+
+	v := reflect.ValueOf(value)
+
+	return c.add(v.Type(), &provider{value: v, resolved: true})
+}
+
+func (c *Container) add(typ reflect.Type, p *provider) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.providers[typ]; ok {
+		return fmt.Errorf("%w: %s", ErrDuplicateProvider, typ)
+	}
+
+	c.providers[typ] = p
+
+	return nil
+}
+
+// Register adds a use case to be constructed and registered with the dispatcher by Wire.
+//
+// The use case is either a pointer to a struct, whose exported fields are resolved from the container,
+// or a constructor function as accepted by Provide. The fields which are already set or tagged with
+// `inject:"-"` are left untouched.
+func (c *Container) Register(request Request, useCase interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.useCases = append(c.useCases, containerUseCase{request: request, useCase: useCase})
+}
+
+// Wire constructs the registered use cases and registers them with the dispatcher, see Dispatcher.RegisterUseCase.
+//
+// The dependencies of all the use cases are checked before anything is constructed. If any of them are
+// missing or cyclic, it returns a *DependencyError for every failure, joined together, and registers nothing.
+func (c *Container) Wire(d *Dispatcher) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var errs []error
+
+	for _, uc := range c.useCases {
+		if err := c.check(uc.useCase); err != nil {
+			errs = append(errs, fmt.Errorf("cannot wire use case for %T: %w", uc.request, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	for _, uc := range c.useCases {
+		useCase, err := c.construct(uc.useCase)
+		if err != nil {
+			return fmt.Errorf("cannot wire use case for %T: %w", uc.request, err)
+		}
+
+		if err := d.RegisterUseCase(uc.request, useCase); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Construct builds the given use case, see Register, and returns it, so it can be passed to Adapt.
+//
+// The dependencies are checked before anything is constructed. It returns a *DependencyError
+// if any of them are missing or cyclic.
+func (c *Container) Construct(useCase interface{}) (interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.check(useCase); err != nil {
+		return nil, err
+	}
+
+	return c.construct(useCase)
+}
+
+// Validate checks that the dependencies of every provider are provided and free of cycles
+// without constructing anything.
+//
+// It returns a *DependencyError for every failure, joined together.
+func (c *Container) Validate() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	checked := make(map[reflect.Type]bool)
+
+	var errs []error
+
+	for _, typ := range sortedTypes(c.providers) {
+		if err := c.checkType([]reflect.Type{}, typ, checked); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// check checks the dependencies of the use case.
+func (c *Container) check(useCase interface{}) error {
+	root, deps, err := injectionPoints(useCase)
+	if err != nil {
+		return err
+	}
+
+	checked := make(map[reflect.Type]bool)
+	for _, dep := range deps {
+		if err := c.checkType([]reflect.Type{root}, dep, checked); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkType walks the dependencies of the type depth first, path being the chain of the dependants leading to it.
+//
+// Every type is checked once, so a failure is reported for the first path leading to it only.
+func (c *Container) checkType(path []reflect.Type, typ reflect.Type, checked map[reflect.Type]bool) error {
+	if checked[typ] {
+		return nil
+	}
+	defer func() { checked[typ] = true }()
+
+	path = append(path[:len(path):len(path)], typ)
+
+	for _, dependant := range path[:len(path)-1] {
+		if dependant == typ {
+			return &DependencyError{Err: ErrDependencyCycle, Path: path}
+		}
+	}
+
+	p, ok := c.providers[typ]
+	if !ok {
+		return &DependencyError{Err: ErrMissingDependency, Path: path}
+	}
+
+	for _, dep := range p.deps {
+		if err := c.checkType(path, dep, checked); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// construct builds the checked use case.
+func (c *Container) construct(useCase interface{}) (interface{}, error) {
+	v := reflect.ValueOf(useCase)
+
+	if v.Kind() == reflect.Func {
+		result, err := c.call(v)
+		if err != nil {
+			return nil, err
+		}
+
+		return result.Interface(), nil
+	}
+
+	for _, field := range injectableFields(v) {
+		dep, err := c.resolve(field.Type())
+		if err != nil {
+			return nil, err
+		}
+
+		field.Set(dep)
+	}
+
+	return useCase, nil
+}
+
+func (c *Container) resolve(typ reflect.Type) (reflect.Value, error) {
+	p := c.providers[typ]
+	if p.resolved {
+		return p.value, nil
+	}
+
+	value, err := c.call(p.ctor)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	p.value, p.resolved = value, true
+
+	return value, nil
+}
+
+// call invokes the constructor with its resolved parameters.
+func (c *Container) call(ctor reflect.Value) (reflect.Value, error) {
+	args := make([]reflect.Value, ctor.Type().NumIn())
+	for i := range args {
+		arg, err := c.resolve(ctor.Type().In(i))
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		args[i] = arg
+	}
+
+	results := ctor.Call(args)
+	if len(results) == 2 && !results[1].IsNil() {
+		err, _ := results[1].Interface().(error)
+
+		return reflect.Value{}, fmt.Errorf("cannot construct %s: %w", ctor.Type().Out(0), err)
+	}
+
+	return results[0], nil
+}
+
+// injectionPoints returns the type the use case is constructed as along with the types it depends on.
+func injectionPoints(useCase interface{}) (reflect.Type, []reflect.Type, error) {
+	v := reflect.ValueOf(useCase)
+
+	if v.Kind() == reflect.Func {
+		if !isConstructor(v) {
+			return nil, nil, fmt.Errorf("%w: %T", ErrInvalidProvider, useCase)
+		}
+
+		deps := make([]reflect.Type, v.Type().NumIn())
+		for i := range deps {
+			deps[i] = v.Type().In(i)
+		}
+
+		return v.Type().Out(0), deps, nil
+	}
+
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return nil, nil, fmt.Errorf("%w: %T", ErrInvalidInjectionTarget, useCase)
+	}
+
+	fields := injectableFields(v)
+
+	deps := make([]reflect.Type, len(fields))
+	for i, field := range fields {
+		deps[i] = field.Type()
+	}
+
+	return v.Type(), deps, nil
+}
+
+// injectableFields returns the exported fields of the struct the pointer points to, which are to be resolved.
+func injectableFields(v reflect.Value) []reflect.Value {
+	var fields []reflect.Value
+
+	for i := 0; i < v.Elem().NumField(); i++ {
+		field := v.Elem().Type().Field(i)
+		if !field.IsExported() || field.Tag.Get("inject") == "-" || !v.Elem().Field(i).IsZero() {
+			continue
+		}
+
+		fields = append(fields, v.Elem().Field(i))
+	}
+
+	return fields
+}
+
+func isConstructor(v reflect.Value) bool {
+	if v.Kind() != reflect.Func || v.IsNil() || v.Type().IsVariadic() {
+		return false
+	}
+
+	switch v.Type().NumOut() {
+	case 1:
+		return v.Type().Out(0) != errorType
+	case 2:
+		return v.Type().Out(0) != errorType && v.Type().Out(1) == errorType
+	default:
+		return false
+	}
+}
+
+func sortedTypes(providers map[reflect.Type]*provider) []reflect.Type {
+	types := make([]reflect.Type, 0, len(providers))
+	for typ := range providers {
+		types = append(types, typ)
+	}
+
+	sort.Slice(types, func(i, j int) bool {
+		return types[i].String() < types[j].String()
+	})
+
+	return types
+}
+
+// DependencyError reports a dependency which cannot be resolved along with the chain of its dependants.
+type DependencyError struct {
+	// Err is either ErrMissingDependency or ErrDependencyCycle.
+	Err error
+	// Path leads from the type being constructed to the failing dependency.
+	Path []reflect.Type
+}
+
+// Error renders the path as a tree:
+//
+//	dependency is not provided: *sql.DB
+//	*app.PlaceOrderUseCase
+//	└── app.OrderRepository
+//	    └── *sql.DB (missing)
+func (e *DependencyError) Error() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%v: %s", e.Err, e.Path[len(e.Path)-1])
+
+	for i, typ := range e.Path {
+		b.WriteString("\n")
+
+		if i > 0 {
+			b.WriteString(strings.Repeat("    ", i-1) + "└── ")
+		}
+
+		b.WriteString(typ.String())
+	}
+
+	switch {
+	case errors.Is(e.Err, ErrMissingDependency):
+		b.WriteString(" (missing)")
+	case errors.Is(e.Err, ErrDependencyCycle):
+		b.WriteString(" (cycle)")
+	}
+
+	return b.String()
+}
+
+// Unwrap returns the cause, so it can be checked with errors.Is.
+func (e *DependencyError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,281 @@
+package interactor_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/interactor/v2"
+)
+
+func TestContainer(t *testing.T) {
+	t.Parallel()
+
+	t.Run("the exported fields of a use case struct are resolved", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		container := newTestContainer(t)
+
+		// act
+		useCase, err := container.Construct(&ReserveStockUseCase{Warehouse: "north"})
+
+		// assert
+		require.NoError(t, err)
+
+		reserve, ok := useCase.(*ReserveStockUseCase)
+		require.True(t, ok)
+		assert.Equal(t, "postgres://stock", reserve.Storage.config.DSN)
+		assert.Equal(t, "north", reserve.Warehouse)
+	})
+
+	t.Run("the fields which are already set are kept", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		container := interactor.NewContainer()
+		storage := &Storage{}
+
+		// act
+		useCase, err := container.Construct(&ReserveStockUseCase{Storage: storage})
+
+		// assert
+		require.NoError(t, err)
+		assert.Same(t, storage, useCase.(*ReserveStockUseCase).Storage)
+	})
+
+	t.Run("the parameters of a use case constructor are resolved", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		container := newTestContainer(t)
+
+		// act
+		useCase, err := container.Construct(func(storage *Storage) *ReserveStockUseCase {
+			return &ReserveStockUseCase{Storage: storage}
+		})
+
+		// assert
+		require.NoError(t, err)
+		assert.NotNil(t, useCase.(*ReserveStockUseCase).Storage)
+	})
+
+	t.Run("a provided type is constructed once", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		container := newTestContainer(t)
+
+		// act
+		first, err := container.Construct(&ReserveStockUseCase{})
+		require.NoError(t, err)
+
+		second, err := container.Construct(&ReserveStockUseCase{})
+		require.NoError(t, err)
+
+		// assert
+		assert.Same(t, first.(*ReserveStockUseCase).Storage, second.(*ReserveStockUseCase).Storage)
+	})
+
+	t.Run("a constructor error is returned", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		container := interactor.NewContainer()
+		require.NoError(t, container.Provide(func() (*StockConfig, error) { return nil, errSomeErr }))
+		require.NoError(t, container.Provide(NewStorage))
+
+		// act
+		_, err := container.Construct(&ReserveStockUseCase{})
+
+		// assert
+		require.ErrorIs(t, err, errSomeErr)
+		assert.ErrorContains(t, err, "*interactor_test.StockConfig")
+	})
+
+	t.Run("a missing dependency is reported with the path to it", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		container := interactor.NewContainer()
+		require.NoError(t, container.Provide(NewStorage))
+
+		// act
+		_, err := container.Construct(&ReserveStockUseCase{})
+
+		// assert
+		require.ErrorIs(t, err, interactor.ErrMissingDependency)
+
+		var depErr *interactor.DependencyError
+		require.ErrorAs(t, err, &depErr)
+
+		want := "dependency is not provided: *interactor_test.StockConfig\n" +
+			"*interactor_test.ReserveStockUseCase\n" +
+			"└── *interactor_test.Storage\n" +
+			"    └── *interactor_test.StockConfig (missing)"
+		assert.Equal(t, want, err.Error())
+	})
+
+	t.Run("a dependency cycle is reported", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		container := interactor.NewContainer()
+		require.NoError(t, container.Provide(func(*Egg) *Chicken { return &Chicken{} }))
+		require.NoError(t, container.Provide(func(*Chicken) *Egg { return &Egg{} }))
+
+		// act
+		_, err := container.Construct(func(*Chicken) *ReserveStockUseCase { return &ReserveStockUseCase{} })
+
+		// assert
+		require.ErrorIs(t, err, interactor.ErrDependencyCycle)
+		assert.Contains(t, err.Error(), "        └── *interactor_test.Chicken (cycle)")
+	})
+
+	t.Run("nothing is constructed if a dependency is missing", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		var constructed bool
+
+		container := interactor.NewContainer()
+		require.NoError(t, container.Provide(func() *StockConfig {
+			constructed = true
+
+			return &StockConfig{}
+		}))
+
+		// act
+		_, err := container.Construct(func(*StockConfig, *Storage) *ReserveStockUseCase {
+			return &ReserveStockUseCase{}
+		})
+
+		// assert
+		require.ErrorIs(t, err, interactor.ErrMissingDependency)
+		assert.False(t, constructed)
+	})
+
+	t.Run("invalid providers and use cases are rejected", func(t *testing.T) {
+		t.Parallel()
+
+		container := newTestContainer(t)
+
+		require.ErrorIs(t, container.Provide(NewStorage), interactor.ErrDuplicateProvider)
+		require.ErrorIs(t, container.Provide(&Storage{}), interactor.ErrInvalidProvider)
+		require.ErrorIs(t, container.Provide(func() error { return nil }), interactor.ErrInvalidProvider)
+		require.ErrorIs(t, container.ProvideValue(nil), interactor.ErrInvalidProvider)
+
+		_, err := container.Construct(ReserveStockUseCase{})
+		require.ErrorIs(t, err, interactor.ErrInvalidInjectionTarget)
+	})
+
+	t.Run("all the providers are validated", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		container := interactor.NewContainer()
+		require.NoError(t, container.Provide(NewStorage))
+		require.NoError(t, container.Provide(func(*Egg) *Chicken { return &Chicken{} }))
+		require.NoError(t, container.Provide(func(*Chicken) *Egg { return &Egg{} }))
+
+		// act
+		err := container.Validate()
+
+		// assert
+		require.ErrorIs(t, err, interactor.ErrMissingDependency)
+		require.ErrorIs(t, err, interactor.ErrDependencyCycle)
+	})
+}
+
+func TestContainerWire(t *testing.T) {
+	t.Parallel()
+
+	t.Run("the use cases are constructed and registered with the dispatcher", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		container := newTestContainer(t)
+		container.Register(ReserveStock{}, &ReserveStockUseCase{})
+
+		dispatcher := interactor.NewDispatcher()
+
+		// act
+		err := container.Wire(dispatcher)
+
+		// assert
+		require.NoError(t, err)
+
+		resp := &ReserveStockResponse{}
+		require.NoError(t, dispatcher.Run(context.Background(), ReserveStock{SKU: "book"}, resp))
+		assert.Equal(t, "book reserved in postgres://stock", resp.Receipt)
+	})
+
+	t.Run("the failures of all the use cases are reported and nothing is registered", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		container := interactor.NewContainer()
+		container.Register(ReserveStock{}, &ReserveStockUseCase{})
+		container.Register(TestRequest{}, func(*StockConfig) ConcreteUseCase { return ConcreteUseCase{} })
+
+		dispatcher := interactor.NewDispatcher()
+
+		// act
+		err := container.Wire(dispatcher)
+
+		// assert
+		require.ErrorIs(t, err, interactor.ErrMissingDependency)
+		assert.ErrorContains(t, err, "interactor_test.ReserveStock:")
+		assert.ErrorContains(t, err, "interactor_test.TestRequest:")
+		require.ErrorIs(t, dispatcher.Run(context.Background(), ReserveStock{}, &ReserveStockResponse{}),
+			interactor.ErrUseCaseRunnerNotFound)
+	})
+}
+
+func newTestContainer(t *testing.T) *interactor.Container {
+	t.Helper()
+
+	container := interactor.NewContainer()
+	require.NoError(t, container.ProvideValue(&StockConfig{DSN: "postgres://stock"}))
+	require.NoError(t, container.Provide(NewStorage))
+
+	return container
+}
+
+type StockConfig struct {
+	DSN string
+}
+
+type Storage struct {
+	config *StockConfig
+}
+
+func NewStorage(config *StockConfig) (*Storage, error) {
+	return &Storage{config: config}, nil
+}
+
+type ReserveStock struct {
+	SKU string
+}
+
+type ReserveStockResponse struct {
+	Receipt string
+}
+
+type ReserveStockUseCase struct {
+	Storage   *Storage
+	Warehouse string `inject:"-"`
+}
+
+func (uc *ReserveStockUseCase) Run(_ context.Context, req ReserveStock, res *ReserveStockResponse) error {
+	res.Receipt = req.SKU + " reserved in " + uc.Storage.config.DSN
+
+	return nil
+}
+
+type (
+	Chicken struct{}
+	Egg     struct{}
+)
@@ -0,0 +1,250 @@
+package interactor
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule returns the times a recurring use case is run at.
+type Schedule interface {
+	// Next returns the first run time after the given time or zero time if there is none.
+	Next(after time.Time) time.Time
+}
+
+type interval time.Duration
+
+// Every returns a Schedule which runs at the fixed interval, starting one interval after the scheduler starts.
+//
+// It panics if the interval is not positive.
+func Every(d time.Duration) Schedule {
+	if d <= 0 {
+		panic("interactor: non-positive interval for Every")
+	}
+
+	return interval(d)
+}
+
+func (i interval) Next(after time.Time) time.Time {
+	return after.Add(time.Duration(i))
+}
+
+// cronSchedule is a parsed cron expression. Every field is a bit set of the allowed values.
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	// domStar and dowStar tell whether the day fields are unrestricted, as a day matches
+	// either of them if both are restricted.
+	domStar, dowStar bool
+}
+
+type cronField struct {
+	min, max int
+	names    map[string]int
+}
+
+var (
+	cronMinute = cronField{min: 0, max: 59}
+	cronHour   = cronField{min: 0, max: 23}
+	cronDom    = cronField{min: 1, max: 31}
+	cronMonth  = cronField{min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// Both 0 and 7 stand for Sunday.
+	cronDow = cronField{min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// ParseCron parses a standard five-field cron expression: minute, hour, day of month, month and day of week.
+//
+// The fields support lists (1,15), ranges (1-5), steps (*/15, 0-30/10) and the names of months and days of week
+// (JAN, MON). The descriptors @yearly, @annually, @monthly, @weekly, @daily, @midnight and @hourly are supported
+// as well. If both the day of month and the day of week are restricted, a day matches either of them.
+//
+// The times are computed in the location of the time given to Next.
+func ParseCron(expr string) (Schedule, error) {
+	spec := strings.TrimSpace(expr)
+	if descriptor, ok := cronDescriptors[strings.ToLower(spec)]; ok {
+		spec = descriptor
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("%w: %q: want 5 fields, got %d", ErrInvalidCronExpression, expr, len(fields))
+	}
+
+	var (
+		s    cronSchedule
+		errs [5]error
+	)
+
+	s.minute, errs[0] = cronMinute.parse(fields[0])
+	s.hour, errs[1] = cronHour.parse(fields[1])
+	s.dom, errs[2] = cronDom.parse(fields[2])
+	s.month, errs[3] = cronMonth.parse(fields[3])
+	s.dow, errs[4] = cronDow.parse(fields[4])
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q: %w", ErrInvalidCronExpression, expr, err)
+		}
+	}
+
+	// Sunday may be given as 7.
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+
+	s.domStar = fields[2] == "*" || fields[2] == "?"
+	s.dowStar = fields[4] == "*" || fields[4] == "?"
+
+	return s, nil
+}
+
+// MustParseCron is like ParseCron but panics if the expression is invalid.
+func MustParseCron(expr string) Schedule {
+	s, err := ParseCron(expr)
+	if err != nil {
+		panic(err)
+	}
+
+	return s
+}
+
+func (f cronField) parse(field string) (uint64, error) {
+	var bits uint64
+
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step %q", stepPart)
+			}
+
+			step = n
+		}
+
+		low, high, err := f.parseRange(rangePart, hasStep)
+		if err != nil {
+			return 0, err
+		}
+
+		for v := low; v <= high; v += step {
+			bits |= 1 << v
+		}
+	}
+
+	return bits, nil
+}
+
+func (f cronField) parseRange(part string, hasStep bool) (int, int, error) {
+	if part == "*" || part == "?" {
+		return f.min, f.max, nil
+	}
+
+	lowPart, highPart, isRange := strings.Cut(part, "-")
+
+	low, err := f.parseValue(lowPart)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var high int
+
+	switch {
+	case isRange:
+		if high, err = f.parseValue(highPart); err != nil {
+			return 0, 0, err
+		}
+	case hasStep:
+		// A step applies from the given value up to the maximum, e.g. 5/15.
+		high = f.max
+	default:
+		high = low
+	}
+
+	if low > high {
+		return 0, 0, fmt.Errorf("invalid range %q", part)
+	}
+
+	return low, high, nil
+}
+
+func (f cronField) parseValue(s string) (int, error) {
+	if v, ok := f.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("value %q out of range %d-%d", s, f.min, f.max)
+	}
+
+	return v, nil
+}
+
+// cronSearchYears limits the search for the next run time, e.g. for February 30.
+const cronSearchYears = 5
+
+func (s cronSchedule) Next(after time.Time) time.Time {
+	loc := after.Location()
+
+	// Cron runs at whole minutes.
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := t.Year() + cronSearchYears
+
+	for t.Year() <= limit {
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			t = later(t, time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc))
+		case !s.dayMatches(t):
+			t = later(t, time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc))
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = later(t, time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc))
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+// later returns next if it is after t, or the minute following t otherwise.
+//
+// Wall clock times are ambiguous when the clocks are turned back, e.g. at the end of daylight saving time,
+// and time.Date picks the earlier of the repeated ones, which may precede t.
+func later(t, next time.Time) time.Time {
+	if next.After(t) {
+		return next
+	}
+
+	return t.Add(time.Minute)
+}
+
+func (s cronSchedule) dayMatches(t time.Time) bool {
+	domMatches := s.dom&(1<<uint(t.Day())) != 0
+	dowMatches := s.dow&(1<<uint(t.Weekday())) != 0
+
+	if s.domStar || s.dowStar {
+		return domMatches && dowMatches
+	}
+
+	return domMatches || dowMatches
+}
@@ -0,0 +1,126 @@
+package interactor_test
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata" // The DST tests need the time zone database.
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/interactor/v2"
+)
+
+func TestParseCron(t *testing.T) {
+	t.Parallel()
+
+	// 2023-01-01 is a Sunday.
+	start := time.Date(2023, 1, 1, 10, 15, 30, 0, time.UTC)
+
+	testCases := []struct {
+		expr string
+		want time.Time
+	}{
+		{expr: "* * * * *", want: time.Date(2023, 1, 1, 10, 16, 0, 0, time.UTC)},
+		{expr: "30 * * * *", want: time.Date(2023, 1, 1, 10, 30, 0, 0, time.UTC)},
+		{expr: "10 * * * *", want: time.Date(2023, 1, 1, 11, 10, 0, 0, time.UTC)},
+		{expr: "*/20 * * * *", want: time.Date(2023, 1, 1, 10, 20, 0, 0, time.UTC)},
+		{expr: "5/20 * * * *", want: time.Date(2023, 1, 1, 10, 25, 0, 0, time.UTC)},
+		{expr: "0 3 * * *", want: time.Date(2023, 1, 2, 3, 0, 0, 0, time.UTC)},
+		{expr: "0 9-17/4 * * *", want: time.Date(2023, 1, 1, 13, 0, 0, 0, time.UTC)},
+		{expr: "0 0 1,15 * *", want: time.Date(2023, 1, 15, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 * * MON-FRI", want: time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 * * 7", want: time.Date(2023, 1, 8, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 13 * FRI", want: time.Date(2023, 1, 6, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 29 FEB *", want: time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 30 2 *", want: time.Time{}},
+		{expr: "@hourly", want: time.Date(2023, 1, 1, 11, 0, 0, 0, time.UTC)},
+		{expr: "@daily", want: time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{expr: "@weekly", want: time.Date(2023, 1, 8, 0, 0, 0, 0, time.UTC)},
+		{expr: "@monthly", want: time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{expr: "@yearly", want: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.expr, func(t *testing.T) {
+			t.Parallel()
+
+			schedule, err := interactor.ParseCron(tc.expr)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.want, schedule.Next(start))
+		})
+	}
+
+	t.Run("the time is computed in the given location", func(t *testing.T) {
+		t.Parallel()
+
+		loc := time.FixedZone("UTC+3", 3*60*60)
+		schedule := interactor.MustParseCron("0 3 * * *")
+
+		got := schedule.Next(time.Date(2023, 1, 1, 2, 0, 0, 0, loc))
+
+		assert.Equal(t, time.Date(2023, 1, 1, 3, 0, 0, 0, loc), got)
+	})
+
+	t.Run("the next run is after the given time when the clocks are turned back", func(t *testing.T) {
+		t.Parallel()
+
+		loc, err := time.LoadLocation("America/New_York")
+		require.NoError(t, err)
+
+		// On 2023-11-05 the clocks are turned back from 02:00 EDT to 01:00 EST.
+		edt := time.Date(2023, 11, 5, 1, 50, 0, 0, loc)
+		est := edt.Add(time.Hour)
+		require.Equal(t, "EST", est.Format("MST"))
+
+		testCases := []struct {
+			expr  string
+			after time.Time
+			want  time.Time
+		}{
+			{expr: "* * * * *", after: est.Add(-20 * time.Minute), want: est.Add(-19 * time.Minute)},
+			{expr: "*/15 * * * *", after: edt, want: edt.Add(10 * time.Minute)},
+			{expr: "0 2 * * *", after: est, want: est.Add(10 * time.Minute)},
+		}
+
+		for _, tc := range testCases {
+			got := interactor.MustParseCron(tc.expr).Next(tc.after)
+
+			assert.True(t, got.After(tc.after), "%s: %s is not after %s", tc.expr, got, tc.after)
+			assert.True(t, tc.want.Equal(got), "%s: want %s, got %s", tc.expr, tc.want, got)
+		}
+	})
+
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8",
+		"*/0 * * * *", "5-1 * * * *", "a * * * *", "* * * FOO *"} {
+		expr := expr
+
+		t.Run("invalid "+expr, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := interactor.ParseCron(expr)
+			require.ErrorIs(t, err, interactor.ErrInvalidCronExpression)
+		})
+	}
+}
+
+func TestEvery(t *testing.T) {
+	t.Parallel()
+
+	t.Run("the runs are spaced by the interval", func(t *testing.T) {
+		t.Parallel()
+
+		start := time.Date(2023, 1, 1, 10, 15, 30, 0, time.UTC)
+
+		assert.Equal(t, start.Add(90*time.Second), interactor.Every(90*time.Second).Next(start))
+	})
+
+	t.Run("the interval must be positive", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Panics(t, func() { interactor.Every(0) })
+	})
+}
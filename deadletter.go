@@ -0,0 +1,85 @@
+package interactor
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DeadLetter is a job given up on after its retry policy is exhausted.
+//
+// The job holds the serialized request and the history of the failed attempts.
+type DeadLetter struct {
+	Job            Job       `json:"job"`
+	Error          string    `json:"error"`
+	DeadLetteredAt time.Time `json:"dead_lettered_at"`
+}
+
+// DeadLetterStore stores dead letters.
+type DeadLetterStore interface {
+	// Add stores the dead letter, replacing the stored one of the same job.
+	Add(ctx context.Context, deadLetter DeadLetter) error
+	// List returns all the dead letters in the order they were dead-lettered.
+	List(ctx context.Context) ([]DeadLetter, error)
+	// Get returns the dead letter of the job with the given ID or ErrDeadLetterNotFound.
+	Get(ctx context.Context, id string) (DeadLetter, error)
+	// Delete removes the dead letter of the job with the given ID. Deleting a missing dead letter is not an error.
+	Delete(ctx context.Context, id string) error
+}
+
+// DeadLetters returns all the dead letters of the queue.
+func (q *Queue) DeadLetters(ctx context.Context) ([]DeadLetter, error) {
+	return q.deadLetters.List(ctx)
+}
+
+// DeadLetter returns the dead letter of the job with the given ID or ErrDeadLetterNotFound.
+func (q *Queue) DeadLetter(ctx context.Context, id string) (DeadLetter, error) {
+	return q.deadLetters.Get(ctx, id)
+}
+
+// Requeue moves the dead letter of the job with the given ID back to the queue to be run right away.
+//
+// The job gets a fresh retry policy: the history of its failed attempts is cleared.
+// It returns ErrDeadLetterNotFound if there is no such dead letter and ErrQueueClosed if the queue is shut down.
+func (q *Queue) Requeue(ctx context.Context, id string) error {
+	if q.isClosed() {
+		return ErrQueueClosed
+	}
+
+	deadLetter, err := q.deadLetters.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	job := deadLetter.Job
+	job.Attempts = nil
+	job.RunAt = q.now()
+
+	if err := q.store.Save(ctx, job); err != nil {
+		return fmt.Errorf("cannot requeue: %w", err)
+	}
+
+	if err := q.deadLetters.Delete(ctx, id); err != nil {
+		return fmt.Errorf("cannot requeue: %w", err)
+	}
+
+	q.notify()
+
+	return nil
+}
+
+// PurgeDeadLetters removes all the dead letters and returns the number of removed ones.
+func (q *Queue) PurgeDeadLetters(ctx context.Context) (int, error) {
+	deadLetters, err := q.deadLetters.List(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("cannot purge dead letters: %w", err)
+	}
+
+	for i, deadLetter := range deadLetters {
+		if err := q.deadLetters.Delete(ctx, deadLetter.Job.ID); err != nil {
+			return i, fmt.Errorf("cannot purge dead letters: %w", err)
+		}
+	}
+
+	return len(deadLetters), nil
+}
@@ -0,0 +1,183 @@
+package interactor
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// MemoryDeadLetterStore keeps dead letters in memory.
+type MemoryDeadLetterStore struct {
+	mu          sync.Mutex
+	deadLetters map[string]DeadLetter
+	order       []string
+}
+
+// NewMemoryDeadLetterStore creates a new MemoryDeadLetterStore instance.
+func NewMemoryDeadLetterStore() *MemoryDeadLetterStore {
+	return &MemoryDeadLetterStore{deadLetters: make(map[string]DeadLetter)}
+}
+
+// Add implements DeadLetterStore interface.
+func (s *MemoryDeadLetterStore) Add(_ context.Context, deadLetter DeadLetter) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := deadLetter.Job.ID
+	if _, ok := s.deadLetters[id]; !ok {
+		s.order = append(s.order, id)
+	}
+
+	s.deadLetters[id] = deadLetter
+
+	return nil
+}
+
+// List implements DeadLetterStore interface.
+func (s *MemoryDeadLetterStore) List(context.Context) ([]DeadLetter, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deadLetters := make([]DeadLetter, 0, len(s.order))
+	for _, id := range s.order {
+		deadLetters = append(deadLetters, s.deadLetters[id])
+	}
+
+	return deadLetters, nil
+}
+
+// Get implements DeadLetterStore interface.
+func (s *MemoryDeadLetterStore) Get(_ context.Context, id string) (DeadLetter, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deadLetter, ok := s.deadLetters[id]
+	if !ok {
+		return DeadLetter{}, fmt.Errorf("%w: %s", ErrDeadLetterNotFound, id)
+	}
+
+	return deadLetter, nil
+}
+
+// Delete implements DeadLetterStore interface.
+func (s *MemoryDeadLetterStore) Delete(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.deadLetters[id]; !ok {
+		return nil
+	}
+
+	delete(s.deadLetters, id)
+
+	for i, stored := range s.order {
+		if stored == id {
+			s.order = append(s.order[:i:i], s.order[i+1:]...)
+
+			break
+		}
+	}
+
+	return nil
+}
+
+// FileDeadLetterStore keeps dead letters in files, one per job, in the given directory.
+type FileDeadLetterStore struct {
+	dir string
+}
+
+// NewFileDeadLetterStore creates a new FileDeadLetterStore instance, creating the directory if needed.
+func NewFileDeadLetterStore(dir string) (*FileDeadLetterStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("cannot create dead-letter store: %w", err)
+	}
+
+	return &FileDeadLetterStore{dir: dir}, nil
+}
+
+// Add implements DeadLetterStore interface.
+func (s *FileDeadLetterStore) Add(_ context.Context, deadLetter DeadLetter) error {
+	content, err := json.Marshal(deadLetter)
+	if err != nil {
+		return fmt.Errorf("cannot encode dead letter: %w", err)
+	}
+
+	return writeFileAtomically(s.path(deadLetter.Job.ID), content)
+}
+
+// List implements DeadLetterStore interface.
+func (s *FileDeadLetterStore) List(context.Context) ([]DeadLetter, error) {
+	paths, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("cannot list dead letters: %w", err)
+	}
+
+	deadLetters := make([]DeadLetter, 0, len(paths))
+
+	for _, path := range paths {
+		deadLetter, err := readDeadLetter(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			// Deleted concurrently.
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		deadLetters = append(deadLetters, deadLetter)
+	}
+
+	sort.SliceStable(deadLetters, func(i, j int) bool {
+		return deadLetters[i].DeadLetteredAt.Before(deadLetters[j].DeadLetteredAt)
+	})
+
+	return deadLetters, nil
+}
+
+// Get implements DeadLetterStore interface.
+func (s *FileDeadLetterStore) Get(_ context.Context, id string) (DeadLetter, error) {
+	deadLetter, err := readDeadLetter(s.path(id))
+	if errors.Is(err, fs.ErrNotExist) {
+		return DeadLetter{}, fmt.Errorf("%w: %s", ErrDeadLetterNotFound, id)
+	}
+
+	return deadLetter, err
+}
+
+// Delete implements DeadLetterStore interface.
+func (s *FileDeadLetterStore) Delete(_ context.Context, id string) error {
+	if err := os.Remove(s.path(id)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("cannot delete dead letter: %w", err)
+	}
+
+	return nil
+}
+
+func (s *FileDeadLetterStore) path(id string) string {
+	sum := sha256.Sum256([]byte(id))
+
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func readDeadLetter(path string) (DeadLetter, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return DeadLetter{}, fmt.Errorf("cannot read dead letter: %w", err)
+	}
+
+	var deadLetter DeadLetter
+	if err := json.Unmarshal(content, &deadLetter); err != nil {
+		return DeadLetter{}, fmt.Errorf("cannot decode dead letter from %s: %w", path, err)
+	}
+
+	return deadLetter, nil
+}
@@ -0,0 +1,113 @@
+package interactor_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/interactor/v2"
+)
+
+func TestDeadLetterStores(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		newStore func(t *testing.T) interactor.DeadLetterStore
+	}{
+		{
+			name: "memory",
+			newStore: func(t *testing.T) interactor.DeadLetterStore {
+				return interactor.NewMemoryDeadLetterStore()
+			},
+		},
+		{
+			name: "file",
+			newStore: func(t *testing.T) interactor.DeadLetterStore {
+				store, err := interactor.NewFileDeadLetterStore(t.TempDir())
+				require.NoError(t, err)
+
+				return store
+			},
+		},
+	}
+
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	first := interactor.DeadLetter{
+		Job: interactor.Job{
+			ID:       "123",
+			Type:     "place_order",
+			Payload:  []byte(`{"order_id":123}`),
+			Attempts: []interactor.JobAttempt{{StartedAt: now, FinishedAt: now, Error: "some error"}},
+		},
+		Error:          "some error",
+		DeadLetteredAt: now,
+	}
+	second := interactor.DeadLetter{Job: interactor.Job{ID: "456", Payload: []byte(`{}`)}, DeadLetteredAt: now.Add(time.Second)}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			t.Run("dead letters are listed in the order they were dead-lettered", func(t *testing.T) {
+				t.Parallel()
+
+				// arrange
+				ctx := context.Background()
+				store := tc.newStore(t)
+				require.NoError(t, store.Add(ctx, first))
+				require.NoError(t, store.Add(ctx, second))
+
+				// act
+				deadLetters, err := store.List(ctx)
+
+				// assert
+				require.NoError(t, err)
+				assert.Equal(t, []interactor.DeadLetter{first, second}, deadLetters)
+			})
+
+			t.Run("a dead letter is inspected by the job ID", func(t *testing.T) {
+				t.Parallel()
+
+				// arrange
+				ctx := context.Background()
+				store := tc.newStore(t)
+				require.NoError(t, store.Add(ctx, first))
+
+				// act
+				deadLetter, err := store.Get(ctx, "123")
+
+				// assert
+				require.NoError(t, err)
+				assert.Equal(t, first, deadLetter)
+			})
+
+			t.Run("a deleted dead letter is not found", func(t *testing.T) {
+				t.Parallel()
+
+				// arrange
+				ctx := context.Background()
+				store := tc.newStore(t)
+				require.NoError(t, store.Add(ctx, first))
+
+				// act
+				require.NoError(t, store.Delete(ctx, "123"))
+				require.NoError(t, store.Delete(ctx, "123"))
+
+				// assert
+				_, err := store.Get(ctx, "123")
+				require.ErrorIs(t, err, interactor.ErrDeadLetterNotFound)
+
+				deadLetters, err := store.List(ctx)
+				require.NoError(t, err)
+				assert.Empty(t, deadLetters)
+			})
+		})
+	}
+}
@@ -0,0 +1,304 @@
+package interactor_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/interactor/v2"
+)
+
+var errDeadLetterStoreDown = errors.New("dead-letter store is down")
+
+func TestQueueDeadLetters(t *testing.T) {
+	t.Parallel()
+
+	t.Run("a job is dead-lettered once its retry policy is exhausted", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		var runs int32
+
+		dispatcher := interactor.NewDispatcher()
+		dispatcher.Register(PlaceOrder{}, func(context.Context, interactor.Request, interactor.Response) error {
+			atomic.AddInt32(&runs, 1)
+
+			return errSomeErr
+		})
+
+		failures := make(chan error, 3)
+		queue := newTestQueue(t, dispatcher,
+			interactor.WithQueueRetryPolicy(interactor.RetryPolicy{MaxAttempts: 3}),
+			interactor.WithQueueErrorHandler(func(_ interactor.Job, err error) { failures <- err }),
+		)
+		require.NoError(t, queue.Start())
+
+		// act
+		id, err := queue.Enqueue(context.Background(), PlaceOrder{OrderID: 123})
+		require.NoError(t, err)
+
+		// assert
+		require.NotErrorIs(t, <-failures, interactor.ErrJobDeadLettered)
+		require.NotErrorIs(t, <-failures, interactor.ErrJobDeadLettered)
+
+		err = <-failures
+		require.ErrorIs(t, err, interactor.ErrJobDeadLettered)
+		require.ErrorIs(t, err, errSomeErr)
+
+		deadLetter, err := queue.DeadLetter(context.Background(), id)
+		require.NoError(t, err)
+		assert.EqualValues(t, 3, atomic.LoadInt32(&runs))
+		assert.Equal(t, "some error", deadLetter.Error)
+		assert.Equal(t, "place_order", deadLetter.Job.Type)
+		assert.JSONEq(t, `{"key":"","order_id":123}`, string(deadLetter.Job.Payload))
+		require.Len(t, deadLetter.Job.Attempts, 3)
+
+		for _, attempt := range deadLetter.Job.Attempts {
+			assert.Equal(t, "some error", attempt.Error)
+			assert.False(t, attempt.StartedAt.IsZero())
+		}
+	})
+
+	t.Run("a panicking job is dead-lettered instead of crashing the worker", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		dispatcher := interactor.NewDispatcher()
+		dispatcher.Register(PlaceOrder{}, func(context.Context, interactor.Request, interactor.Response) error {
+			panic("boom")
+		})
+
+		failures := make(chan error, 1)
+		store := interactor.NewMemoryJobStore()
+		queue := newTestQueue(t, dispatcher, interactor.WithQueueStore(store),
+			interactor.WithQueueErrorHandler(func(_ interactor.Job, err error) { failures <- err }),
+		)
+		require.NoError(t, queue.Start())
+
+		// act
+		id, err := queue.Enqueue(context.Background(), PlaceOrder{OrderID: 123})
+		require.NoError(t, err)
+
+		// assert
+		err = <-failures
+		require.ErrorIs(t, err, interactor.ErrJobDeadLettered)
+		require.ErrorIs(t, err, interactor.ErrUseCasePanicked)
+
+		deadLetter, err := queue.DeadLetter(context.Background(), id)
+		require.NoError(t, err)
+		require.Len(t, deadLetter.Job.Attempts, 1)
+		assert.Contains(t, deadLetter.Job.Attempts[0].Error, "boom")
+		assert.Eventually(t, func() bool { return store.Len() == 0 }, waitFor, tick)
+	})
+
+	t.Run("a failed job is retried after the backoff", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		var runs int32
+
+		dispatcher := interactor.NewDispatcher()
+		dispatcher.Register(PlaceOrder{}, func(context.Context, interactor.Request, interactor.Response) error {
+			atomic.AddInt32(&runs, 1)
+
+			return errSomeErr
+		})
+
+		clock := NewFakeClock()
+		queue := newTestQueue(t, dispatcher,
+			interactor.WithQueueClock(clock.Now),
+			interactor.WithQueuePollInterval(tick),
+			interactor.WithQueueRetryPolicy(interactor.RetryPolicy{
+				MaxAttempts: 2,
+				Backoff:     interactor.ExponentialBackoff(time.Minute, time.Hour),
+			}),
+		)
+		require.NoError(t, queue.Start())
+
+		_, err := queue.Enqueue(context.Background(), PlaceOrder{OrderID: 123})
+		require.NoError(t, err)
+
+		require.Eventually(t, func() bool { return atomic.LoadInt32(&runs) == 1 }, waitFor, tick)
+		time.Sleep(10 * tick)
+		require.EqualValues(t, 1, atomic.LoadInt32(&runs))
+
+		// act
+		clock.Advance(time.Minute)
+
+		// assert
+		assert.Eventually(t, func() bool { return atomic.LoadInt32(&runs) == 2 }, waitFor, tick)
+	})
+
+	t.Run("a job which cannot be dead-lettered is kept and run again", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		var runs int32
+
+		dispatcher := interactor.NewDispatcher()
+		dispatcher.Register(PlaceOrder{}, func(context.Context, interactor.Request, interactor.Response) error {
+			atomic.AddInt32(&runs, 1)
+
+			return errSomeErr
+		})
+
+		clock := NewFakeClock()
+		jobs := interactor.NewMemoryJobStore()
+		deadLetters := &FailingDeadLetterStore{MemoryDeadLetterStore: interactor.NewMemoryDeadLetterStore()}
+		deadLetters.fail.Store(true)
+
+		failures := make(chan error, 10)
+		queue := newTestQueue(t, dispatcher,
+			interactor.WithQueueClock(clock.Now),
+			interactor.WithQueuePollInterval(tick),
+			interactor.WithQueueStore(jobs),
+			interactor.WithDeadLetterStore(deadLetters),
+			interactor.WithQueueErrorHandler(func(_ interactor.Job, err error) { failures <- err }),
+		)
+		require.NoError(t, queue.Start())
+
+		id, err := queue.Enqueue(context.Background(), PlaceOrder{OrderID: 123})
+		require.NoError(t, err)
+
+		require.ErrorIs(t, <-failures, errDeadLetterStoreDown)
+		require.ErrorIs(t, <-failures, errSomeErr)
+		assert.Equal(t, 1, jobs.Len())
+
+		deadLetters.fail.Store(false)
+
+		// act
+		clock.Advance(tick)
+
+		// assert
+		require.ErrorIs(t, <-failures, interactor.ErrJobDeadLettered)
+
+		deadLetter, err := queue.DeadLetter(context.Background(), id)
+		require.NoError(t, err)
+		assert.Len(t, deadLetter.Job.Attempts, 2)
+		assert.EqualValues(t, 2, atomic.LoadInt32(&runs))
+		assert.Zero(t, jobs.Len())
+	})
+
+	t.Run("a job of an unregistered type is dead-lettered right away", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		store := interactor.NewMemoryJobStore()
+		require.NoError(t, store.Save(context.Background(), interactor.Job{ID: "123", Type: "unknown"}))
+
+		queue := newTestQueue(t, interactor.NewDispatcher(),
+			interactor.WithQueueStore(store),
+			interactor.WithQueueRetryPolicy(interactor.RetryPolicy{MaxAttempts: 3}),
+		)
+
+		// act
+		require.NoError(t, queue.Start())
+
+		// assert
+		var deadLetters []interactor.DeadLetter
+
+		require.Eventually(t, func() bool {
+			deadLetters, _ = queue.DeadLetters(context.Background())
+
+			return len(deadLetters) == 1
+		}, waitFor, tick)
+		assert.Len(t, deadLetters[0].Job.Attempts, 1)
+		assert.Contains(t, deadLetters[0].Error, interactor.ErrTypeNotRegistered.Error())
+	})
+
+	t.Run("a requeued dead letter is run again", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		var fixed atomic.Bool
+
+		orders := &OrderLog{}
+		placeOrder := orders.runner()
+
+		dispatcher := interactor.NewDispatcher()
+		dispatcher.Register(PlaceOrder{}, func(ctx context.Context, req interactor.Request, resp interactor.Response) error {
+			if !fixed.Load() {
+				return errSomeErr
+			}
+
+			return placeOrder(ctx, req, resp)
+		})
+
+		queue := newTestQueue(t, dispatcher)
+		require.NoError(t, queue.Start())
+
+		id, err := queue.Enqueue(context.Background(), PlaceOrder{OrderID: 123})
+		require.NoError(t, err)
+
+		require.Eventually(t, func() bool {
+			_, err := queue.DeadLetter(context.Background(), id)
+
+			return err == nil
+		}, waitFor, tick)
+
+		fixed.Store(true)
+
+		// act
+		err = queue.Requeue(context.Background(), id)
+
+		// assert
+		require.NoError(t, err)
+		assert.Eventually(t, func() bool { return len(orders.IDs()) == 1 }, waitFor, tick)
+
+		_, err = queue.DeadLetter(context.Background(), id)
+		require.ErrorIs(t, err, interactor.ErrDeadLetterNotFound)
+	})
+
+	t.Run("a missing dead letter cannot be requeued", func(t *testing.T) {
+		t.Parallel()
+
+		queue := newTestQueue(t, interactor.NewDispatcher())
+
+		err := queue.Requeue(context.Background(), "123")
+		require.ErrorIs(t, err, interactor.ErrDeadLetterNotFound)
+	})
+
+	t.Run("all the dead letters are purged", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		ctx := context.Background()
+
+		store := interactor.NewMemoryDeadLetterStore()
+		require.NoError(t, store.Add(ctx, interactor.DeadLetter{Job: interactor.Job{ID: "123"}}))
+		require.NoError(t, store.Add(ctx, interactor.DeadLetter{Job: interactor.Job{ID: "456"}}))
+
+		queue := newTestQueue(t, interactor.NewDispatcher(), interactor.WithDeadLetterStore(store))
+
+		// act
+		purged, err := queue.PurgeDeadLetters(ctx)
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, 2, purged)
+
+		deadLetters, err := queue.DeadLetters(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, deadLetters)
+	})
+}
+
+// FailingDeadLetterStore fails to add dead letters while fail is set.
+type FailingDeadLetterStore struct {
+	*interactor.MemoryDeadLetterStore
+
+	fail atomic.Bool
+}
+
+func (s *FailingDeadLetterStore) Add(ctx context.Context, deadLetter interactor.DeadLetter) error {
+	if s.fail.Load() {
+		return errDeadLetterStoreDown
+	}
+
+	return s.MemoryDeadLetterStore.Add(ctx, deadLetter)
+}
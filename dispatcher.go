@@ -4,37 +4,131 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"sync"
+	"time"
 )
 
 // Dispatcher manages registered UseCaseRunners and dispatches requests to the appropriate UseCaseRunner.
+//
+// It is safe to register use cases, middlewares and hooks concurrently with running use cases.
 type Dispatcher struct {
+	mu             sync.RWMutex
 	useCaseRunners map[reflect.Type]UseCaseRunnerFn
+	useCases       []registeredUseCase
+	started        []registeredUseCase
+	middlewares    []Middleware
+	hooks          dispatchHooks
+	executor       Executor
+	publisher      *Publisher
+
+	flights          inFlightTracker
+	cancelOnShutdown bool
 }
 
+// DispatcherOption configures a Dispatcher.
+type DispatcherOption func(*Dispatcher)
+
 // NewDispatcher creates a new Dispatcher instance.
-func NewDispatcher() *Dispatcher {
-	return &Dispatcher{
+func NewDispatcher(opts ...DispatcherOption) *Dispatcher {
+	d := &Dispatcher{
 		useCaseRunners: make(map[reflect.Type]UseCaseRunnerFn),
+		executor:       GoExecutor,
+	}
+	for _, opt := range opts {
+		opt(d)
 	}
+
+	return d
 }
 
 // Register registers the given UseCaseRunner for the provided request type.
 func (d *Dispatcher) Register(request Request, runner UseCaseRunnerFn) {
-	requestType := reflect.TypeOf(request)
+	d.register(reflect.TypeOf(request), runner, nil)
+}
+
+// register registers the runner, replacing the use case previously registered for the request type, if any.
+func (d *Dispatcher) register(requestType reflect.Type, runner UseCaseRunnerFn, useCase interface{}) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
 	d.useCaseRunners[requestType] = runner
+
+	useCases := make([]registeredUseCase, 0, len(d.useCases)+1)
+	for _, registered := range d.useCases {
+		if registered.reqType != requestType {
+			useCases = append(useCases, registered)
+		}
+	}
+
+	if useCase != nil {
+		useCases = append(useCases, registeredUseCase{reqType: requestType, useCase: useCase})
+	}
+
+	d.useCases = useCases
+}
+
+// Use adds the given middlewares to every use case run by the dispatcher.
+//
+// Middlewares are applied in the order they are added, the first one being the outermost.
+func (d *Dispatcher) Use(middlewares ...Middleware) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.middlewares = appendCopy(d.middlewares, middlewares...)
 }
 
 // Run runs a use case with the given Request and writes the result to the provided Response.
 //
 // It returns nil if the use case was executed successfully.
 // It returns ErrUseCaseRunnerNotFound  if the use case runner is not registered for the Request type.
+// It returns ErrDispatcherClosed if the dispatcher is shut down.
 func (d *Dispatcher) Run(ctx context.Context, req Request, resp Response) error {
 	reqType := reflect.TypeOf(req)
 
+	ctx, run, err := d.flights.begin(ctx, d, reqType)
+	if err != nil {
+		return err
+	}
+	defer d.flights.end(run)
+
+	d.mu.RLock()
 	runner, ok := d.useCaseRunners[reqType]
+	middlewares, hooks := d.middlewares, d.hooks
+	d.mu.RUnlock()
+
+	event := DispatchEvent{RequestType: reqType, Request: req, Response: resp}
+
 	if !ok {
-		return fmt.Errorf("%w: %s", ErrUseCaseRunnerNotFound, reqType)
+		event.Err = fmt.Errorf("%w: %s", ErrUseCaseRunnerNotFound, reqType)
+		fireHooks(ctx, hooks.notFound, event)
+
+		return event.Err
 	}
 
-	return runner(ctx, req, resp)
+	fireHooks(ctx, hooks.before, event)
+
+	runner = Chain(runner, middlewares...)
+
+	start := time.Now()
+	if d.publisher != nil {
+		event.Err = runRecordingEvents(ctx, d.publisher, func(ctx context.Context) error {
+			return runner(ctx, req, resp)
+		})
+	} else {
+		event.Err = runner(ctx, req, resp)
+	}
+	event.Duration = time.Since(start)
+
+	fireHooks(ctx, hooks.after, event)
+
+	if event.Err != nil {
+		fireHooks(ctx, hooks.onError, event)
+	}
+
+	return event.Err
+}
+
+// appendCopy appends the items to a copy of the slice, so the snapshots taken by running use cases stay intact.
+func appendCopy[T any](s []T, items ...T) []T {
+	return append(append(make([]T, 0, len(s)+len(items)), s...), items...)
 }
@@ -12,4 +12,38 @@ var (
 	ErrSecondArgHasInvalidType       = errors.New("second input argument must implement Request interface")
 	ErrThirdArgHasInvalidType        = errors.New("third input argument must implement Response interface")
 	ErrResultTypeMismatch            = errors.New("result type mismatch")
+	ErrBulkheadFull                  = errors.New("bulkhead is full")
+	ErrRateLimited                   = errors.New("rate limit exceeded")
+	ErrIdempotencyInProgress         = errors.New("request with the same idempotency key is in progress")
+	ErrIdempotencyReplayed           = errors.New("replayed error of the request with the same idempotency key")
+	ErrResponseNotStorable           = errors.New("response cannot be stored as JSON without losing data")
+	ErrUnauthenticated               = errors.New("principal is not authenticated")
+	ErrForbidden                     = errors.New("principal is not allowed to run the request")
+	ErrAuditFailed                   = errors.New("audit record cannot be written")
+	ErrUseCasePanicked               = errors.New("use case panicked")
+	ErrExecutorClosed                = errors.New("executor is shut down")
+	ErrBatchAborted                  = errors.New("call is not run as the batch is aborted")
+	ErrNotificationTypeMismatch      = errors.New("notification type mismatch")
+	ErrNotificationHandlerPanicked   = errors.New("notification handler panicked")
+	ErrNotACommand                   = errors.New("request is not a command")
+	ErrNotAQuery                     = errors.New("request is not a query")
+	ErrNoEventRecorder               = errors.New("context has no event recorder")
+	ErrEventPublishFailed            = errors.New("recorded events cannot be published")
+	ErrTypeNotRegistered             = errors.New("type is not registered")
+	ErrRequestNotStorable            = errors.New("request cannot be stored as JSON without losing data")
+	ErrQueueClosed                   = errors.New("queue is shut down")
+	ErrJobDeadLettered               = errors.New("job is moved to the dead-letter store")
+	ErrDeadLetterNotFound            = errors.New("dead letter not found")
+	ErrNoTransaction                 = errors.New("context has no transaction")
+	ErrMessageDeadLettered           = errors.New("outbox message is moved to the dead-letter store")
+	ErrInvalidCronExpression         = errors.New("invalid cron expression")
+	ErrDuplicateSchedule             = errors.New("schedule with the same name is already added")
+	ErrSchedulerClosed               = errors.New("scheduler is shut down")
+	ErrDispatcherClosed              = errors.New("dispatcher is shut down")
+	ErrScopeClosed                   = errors.New("scope is closed")
+	ErrInvalidProvider               = errors.New("provider must be a function returning a value and optionally an error")
+	ErrDuplicateProvider             = errors.New("type is already provided")
+	ErrInvalidInjectionTarget        = errors.New("use case must be a pointer to a struct or a constructor function")
+	ErrMissingDependency             = errors.New("dependency is not provided")
+	ErrDependencyCycle               = errors.New("dependency cycle")
 )
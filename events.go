@@ -0,0 +1,95 @@
+package interactor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+type eventRecorderCtxKey struct{}
+
+// eventRecorder collects the domain events recorded during a dispatch.
+type eventRecorder struct {
+	mu     sync.Mutex
+	events []Notification
+}
+
+func (r *eventRecorder) record(events ...Notification) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events = append(r.events, events...)
+}
+
+func (r *eventRecorder) recorded() []Notification {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.events
+}
+
+// RecordEvent records a domain event to be published once the use case being run succeeds.
+//
+// It returns ErrNoEventRecorder if the context does not come from a dispatcher configured with WithEventPublisher.
+func RecordEvent(ctx context.Context, event Notification) error {
+	recorder, _ := ctx.Value(eventRecorderCtxKey{}).(*eventRecorder)
+	if recorder == nil {
+		return ErrNoEventRecorder
+	}
+
+	recorder.record(event)
+
+	return nil
+}
+
+// WithEventPublisher makes the dispatcher collect the domain events recorded with RecordEvent
+// and publish them to the given publisher after the use case succeeds.
+//
+// The events are published in the order they are recorded, after all the middlewares return, so e.g. a transaction
+// is committed by then. If the use case fails, its events are discarded.
+//
+// The events recorded by a nested dispatch are passed to the enclosing one at the point the nested dispatch returns,
+// so they are published after the events recorded before it and before the ones recorded after it,
+// and only once the outermost dispatch succeeds. The events of a failed nested dispatch are discarded.
+//
+// All the events are published even if some of them fail to. The failures are reported as ErrEventPublishFailed,
+// even though the use case has succeeded.
+func WithEventPublisher(publisher *Publisher) DispatcherOption {
+	return func(d *Dispatcher) {
+		d.publisher = publisher
+	}
+}
+
+// runRecordingEvents runs the given function with a new event recorder and handles the recorded events.
+func runRecordingEvents(ctx context.Context, publisher *Publisher, run func(ctx context.Context) error) error {
+	parent, _ := ctx.Value(eventRecorderCtxKey{}).(*eventRecorder)
+	recorder := &eventRecorder{}
+
+	if err := run(context.WithValue(ctx, eventRecorderCtxKey{}, recorder)); err != nil {
+		return err
+	}
+
+	if parent != nil {
+		parent.record(recorder.recorded()...)
+
+		return nil
+	}
+
+	// The handlers must not record events into the recorder which is already drained.
+	ctx = context.WithValue(ctx, eventRecorderCtxKey{}, (*eventRecorder)(nil))
+
+	var errs []error
+
+	for _, event := range recorder.recorded() {
+		if err := publisher.Publish(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %w", ErrEventPublishFailed, errors.Join(errs...))
+	}
+
+	return nil
+}
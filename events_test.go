@@ -0,0 +1,224 @@
+package interactor_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/interactor/v2"
+)
+
+func TestEventPublishing(t *testing.T) {
+	t.Parallel()
+
+	t.Run("the recorded events are published in order after the use case succeeds", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		published := &publishedEvents{}
+		dispatcher := interactor.NewDispatcher(interactor.WithEventPublisher(published.publisher()))
+		dispatcher.Register(TestRequest{}, recordingRunner(1, 2, 3))
+
+		// act
+		err := dispatcher.Run(context.Background(), TestRequest{}, &TestResponse{})
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, []int{1, 2, 3}, published.orderIDs())
+	})
+
+	t.Run("the recorded events are discarded if the use case fails", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		published := &publishedEvents{}
+		dispatcher := interactor.NewDispatcher(interactor.WithEventPublisher(published.publisher()))
+		dispatcher.Register(TestRequest{}, func(ctx context.Context, req interactor.Request, resp interactor.Response) error {
+			_ = recordingRunner(1)(ctx, req, resp)
+
+			return errSomeErr
+		})
+
+		// act
+		err := dispatcher.Run(context.Background(), TestRequest{}, &TestResponse{})
+
+		// assert
+		require.ErrorIs(t, err, errSomeErr)
+		assert.Empty(t, published.orderIDs())
+	})
+
+	t.Run("the events are published after the transaction is committed", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		fake := &FakeDB{}
+
+		var commitsBeforePublishing int
+
+		publisher := interactor.NewPublisher()
+		publisher.Subscribe(OrderPlaced{}, func(context.Context, interactor.Notification) error {
+			_, commitsBeforePublishing, _ = fake.Stats()
+
+			return nil
+		})
+
+		dispatcher := interactor.NewDispatcher(interactor.WithEventPublisher(publisher))
+		dispatcher.Use(interactor.Transactional(fake.Open(), nil))
+		dispatcher.Register(TestRequest{}, recordingRunner(1))
+
+		// act
+		err := dispatcher.Run(context.Background(), TestRequest{}, &TestResponse{})
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, 1, commitsBeforePublishing)
+	})
+
+	t.Run("the events of a nested dispatch are published in place by the outermost one", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		published := &publishedEvents{}
+		dispatcher := interactor.NewDispatcher(interactor.WithEventPublisher(published.publisher()))
+
+		var publishedByNested []int
+
+		dispatcher.Register(PlaceOrder{}, recordingRunner(2, 3))
+		dispatcher.Register(TestRequest{}, func(ctx context.Context, req interactor.Request, resp interactor.Response) error {
+			_ = recordingRunner(1)(ctx, req, resp)
+
+			if err := dispatcher.Run(ctx, PlaceOrder{}, &PlaceOrderResponse{}); err != nil {
+				return err
+			}
+
+			publishedByNested = published.orderIDs()
+
+			return recordingRunner(4)(ctx, req, resp)
+		})
+
+		// act
+		err := dispatcher.Run(context.Background(), TestRequest{}, &TestResponse{})
+
+		// assert
+		require.NoError(t, err)
+		assert.Empty(t, publishedByNested)
+		assert.Equal(t, []int{1, 2, 3, 4}, published.orderIDs())
+	})
+
+	t.Run("the events of a failed nested dispatch are discarded", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		published := &publishedEvents{}
+		dispatcher := interactor.NewDispatcher(interactor.WithEventPublisher(published.publisher()))
+
+		dispatcher.Register(PlaceOrder{}, func(ctx context.Context, req interactor.Request, resp interactor.Response) error {
+			_ = recordingRunner(2)(ctx, req, resp)
+
+			return errSomeErr
+		})
+		dispatcher.Register(TestRequest{}, func(ctx context.Context, req interactor.Request, resp interactor.Response) error {
+			_ = recordingRunner(1)(ctx, req, resp)
+			_ = dispatcher.Run(ctx, PlaceOrder{}, &PlaceOrderResponse{})
+
+			return recordingRunner(3)(ctx, req, resp)
+		})
+
+		// act
+		err := dispatcher.Run(context.Background(), TestRequest{}, &TestResponse{})
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, []int{1, 3}, published.orderIDs())
+	})
+
+	t.Run("all the events are published even if some fail to", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		published := &publishedEvents{}
+
+		publisher := published.publisher()
+		publisher.Subscribe(OrderPlaced{}, interactor.Handle(func(_ context.Context, n OrderPlaced) error {
+			if n.OrderID == 1 {
+				return errSomeErr
+			}
+
+			return nil
+		}))
+
+		dispatcher := interactor.NewDispatcher(interactor.WithEventPublisher(publisher))
+		dispatcher.Register(TestRequest{}, recordingRunner(1, 2))
+
+		// act
+		err := dispatcher.Run(context.Background(), TestRequest{}, &TestResponse{})
+
+		// assert
+		require.ErrorIs(t, err, interactor.ErrEventPublishFailed)
+		require.ErrorIs(t, err, errSomeErr)
+		assert.Equal(t, []int{1, 2}, published.orderIDs())
+	})
+
+	t.Run("an event cannot be recorded outside of a dispatch", func(t *testing.T) {
+		t.Parallel()
+
+		err := interactor.RecordEvent(context.Background(), OrderPlaced{})
+		require.ErrorIs(t, err, interactor.ErrNoEventRecorder)
+	})
+
+	t.Run("an event cannot be recorded by a dispatcher without a publisher", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		dispatcher := interactor.NewDispatcher()
+		dispatcher.Register(TestRequest{}, recordingRunner(1))
+
+		// act
+		err := dispatcher.Run(context.Background(), TestRequest{}, &TestResponse{})
+
+		// assert
+		require.ErrorIs(t, err, interactor.ErrNoEventRecorder)
+	})
+}
+
+// recordingRunner records OrderPlaced events with the given order IDs.
+func recordingRunner(orderIDs ...int) interactor.UseCaseRunnerFn {
+	return func(ctx context.Context, _ interactor.Request, _ interactor.Response) error {
+		for _, id := range orderIDs {
+			if err := interactor.RecordEvent(ctx, OrderPlaced{OrderID: id}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+type publishedEvents struct {
+	mu  sync.Mutex
+	ids []int
+}
+
+func (e *publishedEvents) publisher() *interactor.Publisher {
+	publisher := interactor.NewPublisher()
+	publisher.Subscribe(OrderPlaced{}, interactor.Handle(func(_ context.Context, n OrderPlaced) error {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+
+		e.ids = append(e.ids, n.OrderID)
+
+		return nil
+	}))
+
+	return publisher
+}
+
+func (e *publishedEvents) orderIDs() []int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return append([]int(nil), e.ids...)
+}
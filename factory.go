@@ -0,0 +1,92 @@
+package interactor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// RunnerFactory builds a use case runner.
+//
+// The context carries the scope the runner is built for, see ScopeFromContext, so the factory can register
+// the cleanups of the dependencies it creates. If the runner implements Closer, it is closed along with the scope.
+type RunnerFactory func(ctx context.Context) (UseCaseRunner, error)
+
+// FactoryOption configures a factory registered with RegisterFactory.
+type FactoryOption func(*runnerFactory)
+
+// PerScope makes the factory build a single runner per Scope, shared by all the dispatches
+// with the scope's context, so the runner must be safe for concurrent use if they run concurrently.
+//
+// The dispatches without a scope get a runner of their own.
+func PerScope() FactoryOption {
+	return func(f *runnerFactory) {
+		f.perScope = true
+	}
+}
+
+type runnerFactory struct {
+	reqType  reflect.Type
+	build    func(ctx context.Context, scope *Scope) (UseCaseRunner, error)
+	perScope bool
+}
+
+// RegisterFactory registers the given factory for the provided request type.
+//
+// By default, the factory builds a new runner for every dispatch, so runners with request-level state
+// are never shared. The runner and the dependencies built for the dispatch are cleaned up once it completes,
+// and the cleanup failures are returned along with the use case error, if any.
+func (d *Dispatcher) RegisterFactory(request Request, factory RunnerFactory, opts ...FactoryOption) {
+	f := &runnerFactory{reqType: reflect.TypeOf(request)}
+	for _, opt := range opts {
+		opt(f)
+	}
+
+	f.build = func(ctx context.Context, scope *Scope) (UseCaseRunner, error) {
+		runner, err := factory(context.WithValue(ctx, scopeCtxKey{}, scope))
+		if err != nil {
+			return nil, fmt.Errorf("cannot build use case runner for %s: %w", f.reqType, err)
+		}
+
+		if closer, ok := runner.(Closer); ok {
+			if err := scope.Defer(ctx, closer.Close); err != nil {
+				return nil, err
+			}
+		}
+
+		return runner, nil
+	}
+
+	d.Register(request, f.run)
+}
+
+func (f *runnerFactory) run(ctx context.Context, req Request, resp Response) error {
+	if scope, ok := ScopeFromContext(ctx); ok && f.perScope {
+		runner, err := scope.runner(ctx, f)
+		if err != nil {
+			return err
+		}
+
+		return runner.Run(ctx, req, resp)
+	}
+
+	_, scope := NewScope(ctx)
+
+	runErr := f.runInScope(ctx, scope, req, resp)
+
+	if err := scope.Close(detach(ctx)); err != nil {
+		return errors.Join(runErr, fmt.Errorf("cannot clean up use case runner for %s: %w", f.reqType, err))
+	}
+
+	return runErr
+}
+
+func (f *runnerFactory) runInScope(ctx context.Context, scope *Scope, req Request, resp Response) error {
+	runner, err := f.build(ctx, scope)
+	if err != nil {
+		return err
+	}
+
+	return runner.Run(ctx, req, resp)
+}
@@ -0,0 +1,213 @@
+package interactor_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/interactor/v2"
+)
+
+func TestDispatcherRegisterFactory(t *testing.T) {
+	t.Parallel()
+
+	t.Run("a fresh runner is built and closed for every dispatch", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		journal := &Journal{}
+
+		dispatcher := interactor.NewDispatcher()
+		dispatcher.RegisterFactory(TestRequest{}, newStatefulRunnerFactory(journal, nil))
+
+		// act
+		require.NoError(t, dispatcher.Run(context.Background(), TestRequest{}, &TestResponse{}))
+		require.NoError(t, dispatcher.Run(context.Background(), TestRequest{}, &TestResponse{}))
+
+		// assert
+		want := []string{"build #1", "run #1", "close #1", "build #2", "run #2", "close #2"}
+		assert.Equal(t, want, journal.entries())
+	})
+
+	t.Run("concurrent dispatches never share a runner", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		journal := &Journal{}
+
+		dispatcher := interactor.NewDispatcher()
+		dispatcher.RegisterFactory(TestRequest{}, newStatefulRunnerFactory(journal, nil))
+
+		var wg sync.WaitGroup
+
+		// act
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				assert.NoError(t, dispatcher.Run(context.Background(), TestRequest{}, &TestResponse{}))
+			}()
+		}
+
+		wg.Wait()
+
+		// assert
+		assert.Len(t, journal.entries(), 150)
+	})
+
+	t.Run("a factory error is returned and nothing is run", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		dispatcher := interactor.NewDispatcher()
+		dispatcher.RegisterFactory(TestRequest{}, func(context.Context) (interactor.UseCaseRunner, error) {
+			return nil, errSomeErr
+		})
+
+		// act
+		err := dispatcher.Run(context.Background(), TestRequest{}, &TestResponse{})
+
+		// assert
+		require.ErrorIs(t, err, errSomeErr)
+		assert.ErrorContains(t, err, "interactor_test.TestRequest")
+	})
+
+	t.Run("the dependencies of the dispatch are cleaned up even if the use case fails", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		journal := &Journal{}
+
+		dispatcher := interactor.NewDispatcher()
+		dispatcher.RegisterFactory(TestRequest{}, func(ctx context.Context) (interactor.UseCaseRunner, error) {
+			scope, ok := interactor.ScopeFromContext(ctx)
+			require.True(t, ok)
+
+			err := scope.Defer(ctx, func(context.Context) error {
+				journal.record("release connection")
+
+				return nil
+			})
+
+			return interactor.UseCaseRunnerFn(failingRunner(errSomeErr)), err
+		})
+
+		// act
+		err := dispatcher.Run(context.Background(), TestRequest{}, &TestResponse{})
+
+		// assert
+		require.ErrorIs(t, err, errSomeErr)
+		assert.Equal(t, []string{"release connection"}, journal.entries())
+	})
+
+	t.Run("a cleanup failure is returned along with the use case error", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		errCleanup := fmt.Errorf("cleanup: %w", errUnhealthy)
+
+		dispatcher := interactor.NewDispatcher()
+		dispatcher.RegisterFactory(TestRequest{}, newStatefulRunnerFactory(&Journal{}, errCleanup))
+
+		// act
+		err := dispatcher.Run(context.Background(), TestRequest{}, &TestResponse{})
+
+		// assert
+		require.ErrorIs(t, err, errCleanup)
+	})
+
+	t.Run("a per scope runner is shared by the dispatches within the scope", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		journal := &Journal{}
+
+		dispatcher := interactor.NewDispatcher()
+		dispatcher.RegisterFactory(TestRequest{}, newStatefulRunnerFactory(journal, nil), interactor.PerScope())
+
+		ctx, scope := interactor.NewScope(context.Background())
+
+		// act
+		require.NoError(t, dispatcher.Run(ctx, TestRequest{}, &TestResponse{}))
+		require.NoError(t, dispatcher.Run(ctx, TestRequest{}, &TestResponse{}))
+		require.NoError(t, scope.Close(context.Background()))
+
+		// assert
+		assert.Equal(t, []string{"build #1", "run #1", "run #1", "close #1"}, journal.entries())
+	})
+
+	t.Run("a per scope factory builds a runner per dispatch without a scope", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		journal := &Journal{}
+
+		dispatcher := interactor.NewDispatcher()
+		dispatcher.RegisterFactory(TestRequest{}, newStatefulRunnerFactory(journal, nil), interactor.PerScope())
+
+		// act
+		require.NoError(t, dispatcher.Run(context.Background(), TestRequest{}, &TestResponse{}))
+
+		// assert
+		assert.Equal(t, []string{"build #1", "run #1", "close #1"}, journal.entries())
+	})
+
+	t.Run("a per scope factory fails once the scope is closed", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		dispatcher := interactor.NewDispatcher()
+		dispatcher.RegisterFactory(TestRequest{}, newStatefulRunnerFactory(&Journal{}, nil), interactor.PerScope())
+
+		ctx, scope := interactor.NewScope(context.Background())
+		require.NoError(t, scope.Close(ctx))
+
+		// act
+		err := dispatcher.Run(ctx, TestRequest{}, &TestResponse{})
+
+		// assert
+		require.ErrorIs(t, err, interactor.ErrScopeClosed)
+	})
+}
+
+// StatefulRunner keeps request-level state and is closed once it is no longer used.
+type StatefulRunner struct {
+	id       int
+	journal  *Journal
+	closeErr error
+}
+
+func newStatefulRunnerFactory(journal *Journal, closeErr error) interactor.RunnerFactory {
+	var (
+		mu     sync.Mutex
+		builds int
+	)
+
+	return func(context.Context) (interactor.UseCaseRunner, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		builds++
+		journal.record(fmt.Sprintf("build #%d", builds))
+
+		return &StatefulRunner{id: builds, journal: journal, closeErr: closeErr}, nil
+	}
+}
+
+func (r *StatefulRunner) Run(context.Context, interactor.Request, interactor.Response) error {
+	r.journal.record(fmt.Sprintf("run #%d", r.id))
+
+	return nil
+}
+
+func (r *StatefulRunner) Close(context.Context) error {
+	r.journal.record(fmt.Sprintf("close #%d", r.id))
+
+	return r.closeErr
+}
@@ -0,0 +1,273 @@
+package interactor_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var errFakeDB = errors.New("fake db error")
+
+// FakeDB is a database/sql driver which records the transactions it runs.
+//
+// It keeps a single table: INSERT adds a row of its arguments, SELECT returns the rows in the order
+// they were inserted, respecting LIMIT, and DELETE removes the rows whose first column equals its argument.
+// The writes made in a transaction are applied when it is committed.
+type FakeDB struct {
+	mu        sync.Mutex
+	begins    int
+	commits   int
+	rollbacks int
+	rows      [][]driver.Value
+	queries   []string
+
+	failBegin   bool
+	failCommit  bool
+	failDeletes bool
+}
+
+// Open returns a *sql.DB backed by the fake driver.
+func (db *FakeDB) Open() *sql.DB {
+	return sql.OpenDB(fakeConnector{db: db})
+}
+
+// Stats returns the number of the begun, committed and rolled back transactions.
+func (db *FakeDB) Stats() (int, int, int) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	return db.begins, db.commits, db.rollbacks
+}
+
+// Len returns the number of committed rows.
+func (db *FakeDB) Len() int {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	return len(db.rows)
+}
+
+// Queries returns the executed queries.
+func (db *FakeDB) Queries() []string {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	return append([]string(nil), db.queries...)
+}
+
+func (db *FakeDB) setFailDeletes(fail bool) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.failDeletes = fail
+}
+
+// apply applies a write to the table. It must be called with the lock held.
+func (db *FakeDB) apply(query string, args []driver.Value) error {
+	switch {
+	case strings.HasPrefix(query, "INSERT"):
+		db.rows = append(db.rows, args)
+	case strings.HasPrefix(query, "DELETE"):
+		if db.failDeletes {
+			return errFakeDB
+		}
+
+		rows := db.rows[:0:0]
+		for _, row := range db.rows {
+			if row[0] != args[0] {
+				rows = append(rows, row)
+			}
+		}
+
+		db.rows = rows
+	default:
+		return errors.New("not supported")
+	}
+
+	return nil
+}
+
+type fakeConnector struct {
+	db *FakeDB
+}
+
+func (c fakeConnector) Connect(context.Context) (driver.Conn, error) {
+	return &fakeConn{db: c.db}, nil
+}
+
+func (c fakeConnector) Driver() driver.Driver {
+	return fakeDriver{}
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) {
+	return nil, errors.New("use sql.OpenDB")
+}
+
+type fakeConn struct {
+	db *FakeDB
+	tx *fakeTx
+}
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("not supported")
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+
+	if c.db.failBegin {
+		return nil, errFakeDB
+	}
+
+	c.db.begins++
+	c.tx = &fakeTx{conn: c}
+
+	return c.tx, nil
+}
+
+func (c *fakeConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+
+	c.db.queries = append(c.db.queries, query)
+
+	if c.tx != nil {
+		c.tx.writes = append(c.tx.writes, fakeWrite{query: query, args: values(args)})
+
+		return driver.RowsAffected(1), nil
+	}
+
+	return driver.RowsAffected(1), c.db.apply(query, values(args))
+}
+
+// QueryContext returns the committed rows, leaving out the ones whose first column is among the arguments.
+func (c *fakeConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+
+	c.db.queries = append(c.db.queries, query)
+
+	excluded := make(map[driver.Value]bool, len(args))
+	for _, arg := range args {
+		excluded[arg.Value] = true
+	}
+
+	var rows [][]driver.Value
+
+	for _, row := range c.db.rows {
+		if !excluded[row[0]] {
+			rows = append(rows, row)
+		}
+	}
+
+	if i := strings.LastIndex(query, "LIMIT "); i >= 0 {
+		limit, err := strconv.Atoi(query[i+len("LIMIT "):])
+		if err != nil {
+			return nil, err
+		}
+
+		if limit < len(rows) {
+			rows = rows[:limit]
+		}
+	}
+
+	return &fakeRows{rows: rows}, nil
+}
+
+func values(args []driver.NamedValue) []driver.Value {
+	vals := make([]driver.Value, len(args))
+	for i, arg := range args {
+		vals[i] = arg.Value
+	}
+
+	return vals
+}
+
+type fakeWrite struct {
+	query string
+	args  []driver.Value
+}
+
+type fakeTx struct {
+	conn   *fakeConn
+	writes []fakeWrite
+}
+
+func (tx *fakeTx) Commit() error {
+	db := tx.conn.db
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	tx.conn.tx = nil
+
+	if db.failCommit {
+		return errFakeDB
+	}
+
+	for _, write := range tx.writes {
+		if err := db.apply(write.query, write.args); err != nil {
+			return err
+		}
+	}
+
+	db.commits++
+
+	return nil
+}
+
+func (tx *fakeTx) Rollback() error {
+	db := tx.conn.db
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	tx.conn.tx = nil
+	db.rollbacks++
+
+	return nil
+}
+
+type fakeRows struct {
+	rows [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string {
+	if len(r.rows) == 0 {
+		return nil
+	}
+
+	columns := make([]string, len(r.rows[0]))
+	for i := range columns {
+		columns[i] = "column" + strconv.Itoa(i)
+	}
+
+	return columns
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+
+	return nil
+}
@@ -0,0 +1,216 @@
+package interactor
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Executor runs tasks asynchronously.
+type Executor interface {
+	// Execute schedules the task to be run. If it returns an error, the task is not run.
+	Execute(task func()) error
+}
+
+// ExecutorFunc allows using pure functions as an Executor.
+type ExecutorFunc func(task func()) error
+
+// Execute runs the given task.
+func (fn ExecutorFunc) Execute(task func()) error {
+	return fn(task)
+}
+
+// GoExecutor runs every task in a new goroutine.
+var GoExecutor Executor = ExecutorFunc(func(task func()) error {
+	go task()
+
+	return nil
+})
+
+// PoolExecutor runs tasks on a fixed number of goroutines.
+type PoolExecutor struct {
+	mu     sync.Mutex
+	ready  *sync.Cond
+	tasks  []func()
+	closed bool
+}
+
+// NewPoolExecutor starts the given number of workers, which must be positive.
+//
+// Execute does not block: the tasks wait in an unbounded queue until a worker is free, so a task may schedule
+// more tasks on the same pool. Yet a task waiting for another one to complete deadlocks once all the workers
+// are busy waiting. The executor must be closed once it is no longer used.
+func NewPoolExecutor(workers int) *PoolExecutor {
+	if workers <= 0 {
+		panic("interactor: non-positive number of workers for NewPoolExecutor")
+	}
+
+	e := &PoolExecutor{}
+	e.ready = sync.NewCond(&e.mu)
+
+	for i := 0; i < workers; i++ {
+		go e.work()
+	}
+
+	return e
+}
+
+// Execute implements Executor interface.
+//
+// It returns ErrExecutorClosed if the executor is closed.
+func (e *PoolExecutor) Execute(task func()) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.closed {
+		return ErrExecutorClosed
+	}
+
+	e.tasks = append(e.tasks, task)
+	e.ready.Signal()
+
+	return nil
+}
+
+// Close stops the workers once they finish the queued tasks. The tasks scheduled afterwards are rejected.
+func (e *PoolExecutor) Close() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.closed = true
+	e.ready.Broadcast()
+}
+
+func (e *PoolExecutor) work() {
+	for {
+		task, ok := e.next()
+		if !ok {
+			return
+		}
+
+		task()
+	}
+}
+
+// next waits for a queued task. It returns false once the executor is closed and the queue is drained.
+func (e *PoolExecutor) next() (func(), bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for len(e.tasks) == 0 && !e.closed {
+		e.ready.Wait()
+	}
+
+	if len(e.tasks) == 0 {
+		return nil, false
+	}
+
+	task := e.tasks[0]
+	e.tasks[0] = nil
+	e.tasks = e.tasks[1:]
+
+	return task, true
+}
+
+// Future is the result of an asynchronous run.
+type Future struct {
+	done   chan struct{}
+	cancel context.CancelFunc
+	err    error
+}
+
+// Done returns a channel which is closed when the run completes.
+func (f *Future) Done() <-chan struct{} {
+	return f.done
+}
+
+// Wait blocks until the run completes and returns its error.
+//
+// Use Done to wait with a timeout.
+func (f *Future) Wait() error {
+	<-f.done
+
+	return f.err
+}
+
+// Cancel cancels the context of the run.
+//
+// It does not wait for the run to complete.
+func (f *Future) Cancel() {
+	f.cancel()
+}
+
+// WithExecutor sets the executor RunAsync runs use cases on.
+//
+// By default, each use case is run in a new goroutine, see GoExecutor.
+func WithExecutor(executor Executor) DispatcherOption {
+	return func(d *Dispatcher) {
+		d.executor = executor
+	}
+}
+
+// RunAsync runs a use case asynchronously on the dispatcher's executor.
+//
+// The use case writes its result to a private response, which is copied into the provided one right before
+// the run completes, so the provided response must not be read until the future is done.
+// It is left untouched if the run fails.
+//
+// The use case's context is cancelled when the given context is done or the future is cancelled.
+// If the use case panics, the future completes with ErrUseCasePanicked.
+// If the executor rejects the run, the future completes with its error right away.
+func (d *Dispatcher) RunAsync(ctx context.Context, req Request, resp Response) *Future {
+	ctx, cancel := context.WithCancel(ctx)
+	f := &Future{done: make(chan struct{}), cancel: cancel}
+
+	private, err := newResponseLike(resp)
+	if err != nil {
+		f.complete(fmt.Errorf("cannot run asynchronously: %w", err))
+
+		return f
+	}
+
+	err = d.executor.Execute(func() {
+		if err := runRecovered(ctx, d.Run, req, private); err != nil {
+			f.complete(err)
+
+			return
+		}
+
+		f.complete(copyResponse(resp, private))
+	})
+	if err != nil {
+		f.complete(fmt.Errorf("cannot run asynchronously: %w", err))
+	}
+
+	return f
+}
+
+func (f *Future) complete(err error) {
+	f.err = err
+	f.cancel()
+	close(f.done)
+}
+
+// runRecovered runs the use case, turning a panic into ErrUseCasePanicked.
+func runRecovered(ctx context.Context, runner UseCaseRunnerFn, req Request, resp Response) error {
+	return recovered(ErrUseCasePanicked, func() error {
+		return runner(ctx, req, resp)
+	})
+}
+
+// recovered calls fn, turning a panic into the given error.
+func recovered(panicErr error, fn func() error) error {
+	var err error
+
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("%w: %v", panicErr, r)
+			}
+		}()
+
+		err = fn()
+	}()
+
+	return err
+}
@@ -0,0 +1,266 @@
+package interactor_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/interactor/v2"
+)
+
+func TestDispatcherRunAsync(t *testing.T) {
+	t.Parallel()
+
+	t.Run("the response is written once the run completes", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		useCase := NewBlockingUseCase()
+
+		dispatcher := interactor.NewDispatcher()
+		dispatcher.Register(TestRequest{}, interactor.MustAdapt(useCase))
+
+		var res TestResponse
+		future := dispatcher.RunAsync(context.Background(), TestRequest{id: 123}, &res)
+		useCase.Started(1)
+
+		// act
+		useCase.Release()
+		err := future.Wait()
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, 123, res.result)
+	})
+
+	t.Run("the response is not touched until the run completes", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		started, release := make(chan struct{}), make(chan struct{})
+
+		dispatcher := interactor.NewDispatcher()
+		dispatcher.Register(TestRequest{}, interactor.Must(interactor.Func(
+			func(ctx context.Context, req TestRequest, res *TestResponse) error {
+				res.result = req.id
+				close(started)
+				<-release
+
+				return nil
+			},
+		)))
+
+		var res TestResponse
+		future := dispatcher.RunAsync(context.Background(), TestRequest{id: 123}, &res)
+
+		// act
+		<-started
+		got := res.result
+		close(release)
+
+		// assert
+		require.NoError(t, future.Wait())
+		assert.Zero(t, got)
+		assert.Equal(t, 123, res.result)
+	})
+
+	t.Run("the error of the run is returned", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		dispatcher := interactor.NewDispatcher()
+		dispatcher.Register(TestRequest{}, interactor.MustAdapt(ConcreteUseCase{err: errSomeErr}))
+
+		// act
+		err := dispatcher.RunAsync(context.Background(), TestRequest{}, &TestResponse{}).Wait()
+
+		// assert
+		require.ErrorIs(t, err, errSomeErr)
+	})
+
+	t.Run("a panic of the use case completes the future with an error", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		dispatcher := interactor.NewDispatcher()
+		dispatcher.Register(TestRequest{}, func(context.Context, interactor.Request, interactor.Response) error {
+			panic("boom")
+		})
+
+		// act
+		err := dispatcher.RunAsync(context.Background(), TestRequest{}, &TestResponse{}).Wait()
+
+		// assert
+		require.ErrorIs(t, err, interactor.ErrUseCasePanicked)
+		assert.ErrorContains(t, err, "boom")
+	})
+
+	t.Run("the done channel is closed when the run completes", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		dispatcher := interactor.NewDispatcher()
+
+		// act
+		future := dispatcher.RunAsync(context.Background(), TestRequest{}, &TestResponse{})
+
+		// assert
+		<-future.Done()
+		assertUseCaseRunnerNotFound(t, future.Wait())
+	})
+
+	t.Run("cancelling the future cancels the use case's context", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		useCase := NewBlockingUseCase()
+		defer useCase.Release()
+
+		dispatcher := interactor.NewDispatcher()
+		dispatcher.Register(TestRequest{}, interactor.MustAdapt(useCase))
+
+		future := dispatcher.RunAsync(context.Background(), TestRequest{}, &TestResponse{})
+		useCase.Started(1)
+
+		// act
+		future.Cancel()
+
+		// assert
+		require.ErrorIs(t, future.Wait(), context.Canceled)
+	})
+
+	t.Run("cancelling the parent context cancels the use case's context", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		useCase := NewBlockingUseCase()
+		defer useCase.Release()
+
+		dispatcher := interactor.NewDispatcher()
+		dispatcher.Register(TestRequest{}, interactor.MustAdapt(useCase))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		future := dispatcher.RunAsync(ctx, TestRequest{}, &TestResponse{})
+		useCase.Started(1)
+
+		// act
+		cancel()
+
+		// assert
+		require.ErrorIs(t, future.Wait(), context.Canceled)
+	})
+
+	t.Run("use cases are run on the configured executor", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		var executed int32
+
+		executor := interactor.ExecutorFunc(func(task func()) error {
+			atomic.AddInt32(&executed, 1)
+			go task()
+
+			return nil
+		})
+
+		dispatcher := interactor.NewDispatcher(interactor.WithExecutor(executor))
+		dispatcher.Register(TestRequest{}, interactor.MustAdapt(ConcreteUseCase{}))
+
+		// act
+		err := dispatcher.RunAsync(context.Background(), TestRequest{}, &TestResponse{}).Wait()
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&executed))
+	})
+
+	t.Run("use cases can be run on a pool of workers", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		pool := interactor.NewPoolExecutor(2)
+		defer pool.Close()
+
+		dispatcher := interactor.NewDispatcher(interactor.WithExecutor(pool))
+		dispatcher.Register(TestRequest{}, interactor.MustAdapt(ConcreteUseCase{}))
+
+		responses := make([]TestResponse, 5)
+		futures := make([]*interactor.Future, len(responses))
+
+		// act
+		for i := range responses {
+			futures[i] = dispatcher.RunAsync(context.Background(), TestRequest{id: i}, &responses[i])
+		}
+
+		// assert
+		for i, future := range futures {
+			require.NoError(t, future.Wait())
+			assert.Equal(t, i, responses[i].result)
+		}
+	})
+
+	t.Run("a use case run on a pool may run another one asynchronously", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		pool := interactor.NewPoolExecutor(1)
+		defer pool.Close()
+
+		dispatcher := interactor.NewDispatcher(interactor.WithExecutor(pool))
+		dispatcher.Register(TestRequest{}, interactor.MustAdapt(ConcreteUseCase{}))
+
+		inner := make(chan *interactor.Future, 1)
+		dispatcher.Register(PlaceOrder{}, func(ctx context.Context, _ interactor.Request, _ interactor.Response) error {
+			inner <- dispatcher.RunAsync(ctx, TestRequest{}, &TestResponse{})
+
+			return nil
+		})
+
+		// act
+		err := dispatcher.RunAsync(context.Background(), PlaceOrder{}, &PlaceOrderResponse{}).Wait()
+
+		// assert
+		require.NoError(t, err)
+		require.NoError(t, (<-inner).Wait())
+	})
+
+	t.Run("a closed pool rejects new runs", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		pool := interactor.NewPoolExecutor(1)
+		pool.Close()
+
+		dispatcher := interactor.NewDispatcher(interactor.WithExecutor(pool))
+		dispatcher.Register(TestRequest{}, interactor.MustAdapt(ConcreteUseCase{}))
+
+		// act
+		err := dispatcher.RunAsync(context.Background(), TestRequest{}, &TestResponse{}).Wait()
+
+		// assert
+		require.ErrorIs(t, err, interactor.ErrExecutorClosed)
+	})
+
+	t.Run("a pool must have workers", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Panics(t, func() { interactor.NewPoolExecutor(0) })
+	})
+
+	t.Run("a response must be a pointer", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		dispatcher := interactor.NewDispatcher()
+		dispatcher.Register(TestRequest{}, interactor.MustAdapt(ConcreteUseCase{}))
+
+		// act
+		err := dispatcher.RunAsync(context.Background(), TestRequest{}, TestResponse{}).Wait()
+
+		// assert
+		require.ErrorIs(t, err, interactor.ErrResultTypeMismatch)
+	})
+}
@@ -0,0 +1,34 @@
+package interactor
+
+import "context"
+
+// GatherOption configures Gather.
+type GatherOption func(*BatchOptions)
+
+// WithCancelOnFailure cancels the context of the rest of the calls as soon as one of them fails.
+func WithCancelOnFailure() GatherOption {
+	return func(opts *BatchOptions) {
+		opts.FailFast = true
+	}
+}
+
+// Gather runs all the given calls through the runner concurrently and fills in their responses.
+//
+// The calls share the given context, so they are bound by its deadline. If any of the calls fails,
+// it returns *BatchError reporting all the failures. Use it to build a view from several independent queries:
+//
+//	var user UserResponse
+//	var orders OrdersResponse
+//
+//	err := interactor.Gather(ctx, dispatcher, []interactor.Call{
+//		{Request: GetUser{ID: id}, Response: &user},
+//		{Request: GetOrders{UserID: id}, Response: &orders},
+//	})
+func Gather(ctx context.Context, runner UseCaseRunner, calls []Call, opts ...GatherOption) error {
+	var batchOpts BatchOptions
+	for _, opt := range opts {
+		opt(&batchOpts)
+	}
+
+	return batchError(runCalls(ctx, runner.Run, calls, batchOpts))
+}
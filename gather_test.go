@@ -0,0 +1,156 @@
+package interactor_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/interactor/v2"
+)
+
+func TestGather(t *testing.T) {
+	t.Parallel()
+
+	t.Run("all the responses are filled in", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		dispatcher := interactor.NewDispatcher()
+		dispatcher.Register(TestRequest{}, interactor.MustAdapt(ConcreteUseCase{}))
+		dispatcher.Register(PlaceOrder{}, interactor.MustAdapt(&PlaceOrderUseCase{}))
+
+		var res TestResponse
+		var order PlaceOrderResponse
+
+		// act
+		err := interactor.Gather(context.Background(), dispatcher, []interactor.Call{
+			{Request: TestRequest{id: 123}, Response: &res},
+			{Request: PlaceOrder{OrderID: 456}, Response: &order},
+		})
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, 123, res.result)
+		assert.Equal(t, 456, order.OrderID)
+	})
+
+	t.Run("the calls are run concurrently", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		useCase := NewBlockingUseCase()
+
+		dispatcher := interactor.NewDispatcher()
+		dispatcher.Register(TestRequest{}, interactor.MustAdapt(useCase))
+
+		errs := make(chan error, 1)
+
+		// act
+		go func() { errs <- interactor.Gather(context.Background(), dispatcher, testCalls(3)) }()
+
+		// assert
+		useCase.Started(3)
+		useCase.Release()
+		require.NoError(t, <-errs)
+	})
+
+	t.Run("the failures are combined", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		runner := interactor.UseCaseRunnerFn(func(_ context.Context, req interactor.Request, _ interactor.Response) error {
+			if req.(TestRequest).id > 0 {
+				return errSomeErr
+			}
+
+			return nil
+		})
+
+		// act
+		err := interactor.Gather(context.Background(), runner, testCalls(3))
+
+		// assert
+		require.ErrorIs(t, err, errSomeErr)
+
+		var batchErr *interactor.BatchError
+		require.True(t, errors.As(err, &batchErr))
+		assert.Equal(t, []int{1, 2}, batchErr.Failed())
+	})
+
+	t.Run("a panicking call fails on its own", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		runner := interactor.UseCaseRunnerFn(func(_ context.Context, req interactor.Request, _ interactor.Response) error {
+			if req.(TestRequest).id == 2 {
+				panic("boom")
+			}
+
+			return nil
+		})
+
+		// act
+		err := interactor.Gather(context.Background(), runner, testCalls(3))
+
+		// assert
+		require.ErrorIs(t, err, interactor.ErrUseCasePanicked)
+
+		var batchErr *interactor.BatchError
+		require.True(t, errors.As(err, &batchErr))
+		assert.Equal(t, []int{2}, batchErr.Failed())
+	})
+
+	t.Run("the rest of the calls are cancelled on the first failure if configured", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		useCase := NewBlockingUseCase()
+		defer useCase.Release()
+
+		runner := interactor.UseCaseRunnerFn(func(ctx context.Context, req interactor.Request, resp interactor.Response) error {
+			if _, ok := req.(TestRequest); ok {
+				return interactor.MustAdapt(useCase)(ctx, req, resp)
+			}
+
+			useCase.Started(1)
+
+			return errSomeErr
+		})
+
+		calls := []interactor.Call{
+			{Request: TestRequest{}, Response: &TestResponse{}},
+			{Request: &TestRequest{}, Response: &TestResponse{}},
+		}
+
+		// act
+		err := interactor.Gather(context.Background(), runner, calls, interactor.WithCancelOnFailure())
+
+		// assert
+		require.ErrorIs(t, err, errSomeErr)
+		require.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("the shared deadline is honoured", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		useCase := NewBlockingUseCase()
+		defer useCase.Release()
+
+		dispatcher := interactor.NewDispatcher()
+		dispatcher.Register(TestRequest{}, interactor.MustAdapt(useCase))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		// act
+		err := interactor.Gather(ctx, dispatcher, testCalls(2))
+
+		// assert
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
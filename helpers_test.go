@@ -3,6 +3,10 @@ package interactor_test
 import (
 	"context"
 	"errors"
+	"sync"
+	"time"
+
+	"github.com/screwyprof/interactor/v2"
 )
 
 var errSomeErr = errors.New("some error")
@@ -56,3 +60,344 @@ type InvalidUseCaseWrongResponse struct{}
 func (i InvalidUseCaseWrongResponse) Run(ctx context.Context, req TestRequest, resp struct{}) error {
 	return nil
 }
+
+func succeedingRunner(context.Context, interactor.Request, interactor.Response) error {
+	return nil
+}
+
+func failingRunner(err error) interactor.UseCaseRunnerFn {
+	return func(context.Context, interactor.Request, interactor.Response) error {
+		return err
+	}
+}
+
+// BlockingUseCase signals when it starts and blocks until released or the context is done.
+type BlockingUseCase struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func NewBlockingUseCase() *BlockingUseCase {
+	return &BlockingUseCase{
+		started: make(chan struct{}, 100),
+		release: make(chan struct{}),
+	}
+}
+
+func (uc *BlockingUseCase) Run(ctx context.Context, req TestRequest, res *TestResponse) error {
+	uc.started <- struct{}{}
+
+	select {
+	case <-uc.release:
+		res.result = req.id
+
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Started waits for n runs to start.
+func (uc *BlockingUseCase) Started(n int) {
+	for i := 0; i < n; i++ {
+		<-uc.started
+	}
+}
+
+// Release unblocks all the runs.
+func (uc *BlockingUseCase) Release() {
+	close(uc.release)
+}
+
+// FakeClock is a manually advanced clock.
+type FakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+func NewFakeClock() *FakeClock {
+	return &FakeClock{now: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// Advance moves the clock forward, firing the expired timers.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+
+	pending := c.timers[:0:0]
+
+	for _, timer := range c.timers {
+		if timer.at.After(c.now) {
+			pending = append(pending, timer)
+
+			continue
+		}
+
+		timer.c <- c.now
+	}
+
+	c.timers = pending
+}
+
+func (c *FakeClock) NewTimer(d time.Duration) interactor.Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	timer := &fakeTimer{clock: c, at: c.now.Add(d), c: make(chan time.Time, 1)}
+	if d <= 0 {
+		timer.c <- c.now
+
+		return timer
+	}
+
+	c.timers = append(c.timers, timer)
+
+	return timer
+}
+
+// Timers returns the number of the pending timers.
+func (c *FakeClock) Timers() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.timers)
+}
+
+type fakeTimer struct {
+	clock *FakeClock
+	at    time.Time
+	c     chan time.Time
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	for i, timer := range t.clock.timers {
+		if timer == t {
+			t.clock.timers = append(t.clock.timers[:i:i], t.clock.timers[i+1:]...)
+
+			return true
+		}
+	}
+
+	return false
+}
+
+type CacheableRequest struct {
+	id     int
+	tags   []string
+	filter map[string]int
+	ttl    time.Duration
+}
+
+func (r CacheableRequest) CacheTTL() time.Duration {
+	return r.ttl
+}
+
+type DetailedResponse struct {
+	result int
+	tags   []string
+	owner  *Owner
+}
+
+type Owner struct {
+	Name string
+}
+
+// CountingUseCase counts its runs and echoes the request into the response.
+type CountingUseCase struct {
+	mu   sync.Mutex
+	runs int
+	err  error
+}
+
+func (uc *CountingUseCase) Run(_ context.Context, req CacheableRequest, res *DetailedResponse) error {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	uc.runs++
+
+	res.result = req.id
+	res.tags = append([]string(nil), req.tags...)
+	res.owner = &Owner{Name: "owner"}
+
+	return uc.err
+}
+
+func (uc *CountingUseCase) Runs() int {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	return uc.runs
+}
+
+// CountingMiddleware counts the runs passing through it and records their errors.
+type CountingMiddleware struct {
+	mu    sync.Mutex
+	count int
+	errs  []error
+}
+
+func (m *CountingMiddleware) Middleware() interactor.Middleware {
+	return func(next interactor.UseCaseRunnerFn) interactor.UseCaseRunnerFn {
+		return func(ctx context.Context, req interactor.Request, resp interactor.Response) error {
+			m.mu.Lock()
+			m.count++
+			m.mu.Unlock()
+
+			err := next(ctx, req, resp)
+
+			m.mu.Lock()
+			m.errs = append(m.errs, err)
+			m.mu.Unlock()
+
+			return err
+		}
+	}
+}
+
+func (m *CountingMiddleware) Count() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.count
+}
+
+func (m *CountingMiddleware) Errors() []error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]error(nil), m.errs...)
+}
+
+type PlaceOrder struct {
+	Key     string `json:"key"`
+	OrderID int    `json:"order_id"`
+}
+
+func (r PlaceOrder) IdempotencyKey() string {
+	return r.Key
+}
+
+type PlaceOrderResponse struct {
+	OrderID int `json:"order_id"`
+}
+
+// PlaceOrderUseCase counts the orders placed.
+type PlaceOrderUseCase struct {
+	mu     sync.Mutex
+	placed int
+	err    error
+}
+
+func (uc *PlaceOrderUseCase) Run(_ context.Context, req PlaceOrder, res *PlaceOrderResponse) error {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	uc.placed++
+	res.OrderID = req.OrderID
+
+	return uc.err
+}
+
+func (uc *PlaceOrderUseCase) Placed() int {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	return uc.placed
+}
+
+type User struct {
+	id    string
+	admin bool
+}
+
+func (u *User) ID() string {
+	return u.id
+}
+
+// AdminRequest is a family of requests only admins may run.
+type AdminRequest interface {
+	AdminOnly()
+}
+
+type DeleteUser struct {
+	id string
+}
+
+func (DeleteUser) AdminOnly() {}
+
+type ChangePassword struct {
+	UserID   string `json:"user_id"`
+	Password string `audit:"redact"`
+	Token    string `audit:"-"`
+}
+
+type ChangePasswordResponse struct{}
+
+type GetUser struct {
+	UserID string
+}
+
+type GetUserResponse struct {
+	Name string
+}
+
+type GetUserUseCase struct{}
+
+func (GetUserUseCase) Run(_ context.Context, req GetUser, res *GetUserResponse) error {
+	res.Name = "user " + req.UserID
+
+	return nil
+}
+
+// Journal records the names of the run use cases and handlers.
+type Journal struct {
+	mu   sync.Mutex
+	list []string
+}
+
+func (j *Journal) runner(name string, err error) interactor.UseCaseRunnerFn {
+	return func(context.Context, interactor.Request, interactor.Response) error {
+		j.record(name)
+
+		return err
+	}
+}
+
+func (j *Journal) handler(name string, err error) interactor.NotificationHandlerFn {
+	return func(context.Context, interactor.Notification) error {
+		j.record(name)
+
+		return err
+	}
+}
+
+func (j *Journal) record(name string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.list = append(j.list, name)
+}
+
+func (j *Journal) entries() []string {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return append([]string(nil), j.list...)
+}
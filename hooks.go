@@ -0,0 +1,74 @@
+package interactor
+
+import (
+	"context"
+	"reflect"
+	"time"
+)
+
+// DispatchEvent describes a dispatch.
+//
+// Hooks get a copy of the event, so they cannot alter what the caller gets.
+// The request and the response must be treated as read-only.
+type DispatchEvent struct {
+	// RequestType is the type of the dispatched request.
+	RequestType reflect.Type
+	// Request is the dispatched request.
+	Request Request
+	// Response is the response the use case writes its result to.
+	Response Response
+	// Err is the error returned to the caller. It is not set for OnBefore hooks.
+	Err error
+	// Duration is the time the use case has run for. It is not set for OnBefore and OnNotFound hooks.
+	Duration time.Duration
+}
+
+// Hook is called by the Dispatcher at the certain stages of a dispatch.
+//
+// Hooks have no return value, so they can observe a dispatch, but cannot change its outcome.
+type Hook func(ctx context.Context, event DispatchEvent)
+
+type dispatchHooks struct {
+	before   []Hook
+	after    []Hook
+	onError  []Hook
+	notFound []Hook
+}
+
+func fireHooks(ctx context.Context, hooks []Hook, event DispatchEvent) {
+	for _, hook := range hooks {
+		hook(ctx, event)
+	}
+}
+
+// OnBefore registers a hook called before a use case is run.
+func (d *Dispatcher) OnBefore(hook Hook) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.hooks.before = appendCopy(d.hooks.before, hook)
+}
+
+// OnAfter registers a hook called after a use case has run, whether it succeeded or not.
+func (d *Dispatcher) OnAfter(hook Hook) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.hooks.after = appendCopy(d.hooks.after, hook)
+}
+
+// OnError registers a hook called after a use case has failed.
+func (d *Dispatcher) OnError(hook Hook) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.hooks.onError = appendCopy(d.hooks.onError, hook)
+}
+
+// OnNotFound registers a hook called when no use case is registered for the request type.
+func (d *Dispatcher) OnNotFound(hook Hook) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.hooks.notFound = appendCopy(d.hooks.notFound, hook)
+}
@@ -0,0 +1,161 @@
+package interactor_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/interactor/v2"
+)
+
+func TestDispatcherHooks(t *testing.T) {
+	t.Parallel()
+
+	t.Run("hooks are called around a successful run", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		dispatcher := interactor.NewDispatcher()
+		dispatcher.Register(TestRequest{}, interactor.MustAdapt(ConcreteUseCase{}))
+
+		events := recordHooks(dispatcher)
+
+		// act
+		var res TestResponse
+		err := dispatcher.Run(context.Background(), TestRequest{id: 123}, &res)
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, []string{"before", "after"}, events.stages())
+
+		after := events.get("after")
+		assert.Equal(t, "interactor_test.TestRequest", after.RequestType.String())
+		assert.Equal(t, TestRequest{id: 123}, after.Request)
+		assert.Same(t, &res, after.Response)
+		assert.NoError(t, after.Err)
+		assert.Positive(t, after.Duration)
+	})
+
+	t.Run("hooks are called around a failed run", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		dispatcher := interactor.NewDispatcher()
+		dispatcher.Register(TestRequest{}, interactor.MustAdapt(ConcreteUseCase{err: errSomeErr}))
+
+		events := recordHooks(dispatcher)
+
+		// act
+		err := dispatcher.Run(context.Background(), TestRequest{}, &TestResponse{})
+
+		// assert
+		require.ErrorIs(t, err, errSomeErr)
+		assert.Equal(t, []string{"before", "after", "error"}, events.stages())
+		assert.ErrorIs(t, events.get("error").Err, errSomeErr)
+	})
+
+	t.Run("not found hooks are called when use case not found", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		dispatcher := interactor.NewDispatcher()
+		events := recordHooks(dispatcher)
+
+		// act
+		err := dispatcher.Run(context.Background(), TestRequest{}, &TestResponse{})
+
+		// assert
+		assertUseCaseRunnerNotFound(t, err)
+		assert.Equal(t, []string{"not found"}, events.stages())
+		assert.ErrorIs(t, events.get("not found").Err, interactor.ErrUseCaseRunnerNotFound)
+	})
+
+	t.Run("hooks cannot swallow errors", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		dispatcher := interactor.NewDispatcher()
+		dispatcher.Register(TestRequest{}, interactor.MustAdapt(ConcreteUseCase{err: errSomeErr}))
+		dispatcher.OnAfter(func(_ context.Context, event interactor.DispatchEvent) {
+			event.Err = nil
+		})
+
+		// act
+		err := dispatcher.Run(context.Background(), TestRequest{}, &TestResponse{})
+
+		// assert
+		require.ErrorIs(t, err, errSomeErr)
+	})
+
+	t.Run("hooks can be registered concurrently with runs", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		dispatcher := interactor.NewDispatcher()
+		dispatcher.Register(TestRequest{}, interactor.MustAdapt(ConcreteUseCase{}))
+
+		var wg sync.WaitGroup
+
+		// act
+		for i := 0; i < 10; i++ {
+			wg.Add(2)
+
+			go func() {
+				defer wg.Done()
+				dispatcher.OnBefore(func(context.Context, interactor.DispatchEvent) {})
+			}()
+
+			go func() {
+				defer wg.Done()
+				_ = dispatcher.Run(context.Background(), TestRequest{}, &TestResponse{})
+			}()
+		}
+
+		// assert
+		wg.Wait()
+	})
+}
+
+type recordedEvents struct {
+	mu     sync.Mutex
+	names  []string
+	events map[string]interactor.DispatchEvent
+}
+
+func recordHooks(dispatcher *interactor.Dispatcher) *recordedEvents {
+	r := &recordedEvents{events: make(map[string]interactor.DispatchEvent)}
+
+	dispatcher.OnBefore(r.record("before"))
+	dispatcher.OnAfter(r.record("after"))
+	dispatcher.OnError(r.record("error"))
+	dispatcher.OnNotFound(r.record("not found"))
+
+	return r
+}
+
+func (r *recordedEvents) record(stage string) interactor.Hook {
+	return func(_ context.Context, event interactor.DispatchEvent) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		r.names = append(r.names, stage)
+		r.events[stage] = event
+	}
+}
+
+func (r *recordedEvents) stages() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]string(nil), r.names...)
+}
+
+func (r *recordedEvents) get(stage string) interactor.DispatchEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.events[stage]
+}
@@ -0,0 +1,244 @@
+package interactor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// IdempotentRequest is implemented by requests which carry an idempotency key.
+type IdempotentRequest interface {
+	IdempotencyKey() string
+}
+
+type idempotencyKeyCtxKey struct{}
+
+// WithIdempotencyKey returns a copy of the context carrying the given idempotency key.
+//
+// The key stored in the context takes precedence over the one provided by the request.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyCtxKey{}, key)
+}
+
+type retriedFailuresCtxKey struct{}
+
+// withRetriedFailures returns a copy of the context telling Idempotency not to store failures,
+// as the caller retries them.
+func withRetriedFailures(ctx context.Context) context.Context {
+	return context.WithValue(ctx, retriedFailuresCtxKey{}, true)
+}
+
+// IdempotencyKeyFromContext returns the idempotency key stored in the context, if any.
+func IdempotencyKeyFromContext(ctx context.Context) (string, bool) {
+	key, ok := ctx.Value(idempotencyKeyCtxKey{}).(string)
+
+	return key, ok && key != ""
+}
+
+// IdempotencyRecord is the stored outcome of a run.
+type IdempotencyRecord struct {
+	// Response is the JSON encoded response.
+	Response json.RawMessage `json:"response,omitempty"`
+	// Error is the message of the returned error, if any.
+	Error string `json:"error,omitempty"`
+	// CompletedAt is the time the run completed at.
+	CompletedAt time.Time `json:"completed_at"`
+}
+
+// IdempotencyStore keeps the outcomes of runs by their idempotency keys.
+type IdempotencyStore interface {
+	// Begin reserves the given key for a run.
+	//
+	// It returns the stored record if the key has been completed already,
+	// or ErrIdempotencyInProgress if the key is reserved by another run.
+	Begin(ctx context.Context, key string) (*IdempotencyRecord, error)
+
+	// Complete stores the outcome of the run which reserved the key.
+	Complete(ctx context.Context, key string, record IdempotencyRecord) error
+
+	// Release frees the key without storing an outcome, so the run can be retried.
+	Release(ctx context.Context, key string) error
+}
+
+// Idempotency returns a middleware which runs a use case at most once per idempotency key.
+//
+// The key is taken from the context, see WithIdempotencyKey, or from the request, see IdempotentRequest.
+// Runs without a key are passed through. Keys are scoped by the request type.
+//
+// The outcome of the first run is stored and replayed for the repeated runs: the response is decoded into
+// the provided one and the error is returned wrapped into ErrIdempotencyReplayed. The responses must be
+// JSON serialisable without losing data: a response with unexported fields, which JSON leaves out,
+// is rejected with ErrResponseNotStorable before the use case is run, unless the type encodes itself.
+// Runs which fail with a context error are not stored, so they can be retried. Neither are the failures
+// of the requests dispatched by OutboxRelay, which retries them.
+//
+// Concurrent runs with the same key are rejected with ErrIdempotencyInProgress.
+func Idempotency(store IdempotencyStore) Middleware {
+	// The outcomes of the storability checks by response type.
+	var storable sync.Map
+
+	return func(next UseCaseRunnerFn) UseCaseRunnerFn {
+		return func(ctx context.Context, req Request, resp Response) error {
+			key, ok := idempotencyKey(ctx, req)
+			if !ok {
+				return next(ctx, req, resp)
+			}
+
+			if err := ensureStorable(&storable, resp); err != nil {
+				return err
+			}
+
+			record, err := store.Begin(ctx, key)
+			if err != nil {
+				return err
+			}
+
+			if record != nil {
+				return replay(record, resp)
+			}
+
+			return runOnce(ctx, store, key, next, req, resp)
+		}
+	}
+}
+
+func runOnce(ctx context.Context, store IdempotencyStore, key string, next UseCaseRunnerFn, req Request, resp Response) error {
+	defer func() {
+		if r := recover(); r != nil {
+			_ = store.Release(detach(ctx), key)
+			panic(r)
+		}
+	}()
+
+	runErr := next(ctx, req, resp)
+
+	retried, _ := ctx.Value(retriedFailuresCtxKey{}).(bool)
+	if runErr != nil && retried ||
+		errors.Is(runErr, context.Canceled) || errors.Is(runErr, context.DeadlineExceeded) {
+		return errors.Join(runErr, store.Release(detach(ctx), key))
+	}
+
+	record, err := newIdempotencyRecord(resp, runErr)
+	if err != nil {
+		return errors.Join(err, store.Release(detach(ctx), key))
+	}
+
+	if err := store.Complete(detach(ctx), key, record); err != nil {
+		return errors.Join(runErr, err)
+	}
+
+	return runErr
+}
+
+func newIdempotencyRecord(resp Response, runErr error) (IdempotencyRecord, error) {
+	record := IdempotencyRecord{CompletedAt: time.Now()}
+
+	if runErr != nil {
+		record.Error = runErr.Error()
+
+		return record, nil
+	}
+
+	encoded, err := json.Marshal(resp)
+	if err != nil {
+		return record, fmt.Errorf("cannot encode response: %w", err)
+	}
+
+	record.Response = encoded
+
+	return record, nil
+}
+
+func replay(record *IdempotencyRecord, resp Response) error {
+	if record.Error != "" {
+		return fmt.Errorf("%w: %s", ErrIdempotencyReplayed, record.Error)
+	}
+
+	if len(record.Response) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(record.Response, resp); err != nil {
+		return fmt.Errorf("cannot decode stored response: %w", err)
+	}
+
+	return nil
+}
+
+// ensureStorable checks that the response survives a JSON round trip. The outcomes are cached in the given map.
+func ensureStorable(checked *sync.Map, resp Response) error {
+	t := reflect.TypeOf(resp)
+
+	if outcome, ok := checked.Load(t); ok {
+		// The passed checks are stored as nil.
+		err, _ := outcome.(error)
+
+		return err
+	}
+
+	var err error
+	if field := lossyField(t, make(map[reflect.Type]bool)); field != "" {
+		err = fmt.Errorf("%w: %s has unexported field %s", ErrResponseNotStorable, t, field)
+	}
+
+	checked.Store(t, err)
+
+	return err
+}
+
+// lossyField returns the name of the first unexported field JSON would leave out, if any.
+func lossyField(t reflect.Type, visited map[reflect.Type]bool) string {
+	if visited[t] {
+		return ""
+	}
+	visited[t] = true
+
+	if t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType) ||
+		t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType) {
+		return ""
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return lossyField(t.Elem(), visited)
+	case reflect.Map:
+		return lossyField(t.Elem(), visited)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.Tag.Get("json") == "-" {
+				continue
+			}
+
+			embedded := field.Anonymous && (field.Type.Kind() == reflect.Struct ||
+				field.Type.Kind() == reflect.Ptr && field.Type.Elem().Kind() == reflect.Struct)
+			if !field.IsExported() && !embedded {
+				return t.String() + "." + field.Name
+			}
+
+			if name := lossyField(field.Type, visited); name != "" {
+				return name
+			}
+		}
+	default:
+	}
+
+	return ""
+}
+
+func idempotencyKey(ctx context.Context, req Request) (string, bool) {
+	key, ok := IdempotencyKeyFromContext(ctx)
+	if r, isIdempotent := req.(IdempotentRequest); !ok && isIdempotent {
+		key = r.IdempotencyKey()
+	}
+
+	if key == "" {
+		return "", false
+	}
+
+	return RequestTypeKey(ctx, req) + ":" + key, true
+}
@@ -0,0 +1,161 @@
+package interactor
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// MemoryIdempotencyStore keeps idempotency records in memory.
+type MemoryIdempotencyStore struct {
+	mu      sync.Mutex
+	records map[string]*IdempotencyRecord
+}
+
+// NewMemoryIdempotencyStore creates a new MemoryIdempotencyStore instance.
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{records: make(map[string]*IdempotencyRecord)}
+}
+
+// Begin implements IdempotencyStore interface.
+func (s *MemoryIdempotencyStore) Begin(_ context.Context, key string) (*IdempotencyRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, ok := s.records[key]
+	if !ok {
+		s.records[key] = nil
+
+		return nil, nil
+	}
+
+	if record == nil {
+		return nil, fmt.Errorf("%w: %s", ErrIdempotencyInProgress, key)
+	}
+
+	return record, nil
+}
+
+// Complete implements IdempotencyStore interface.
+func (s *MemoryIdempotencyStore) Complete(_ context.Context, key string, record IdempotencyRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.records[key] = &record
+
+	return nil
+}
+
+// Release implements IdempotencyStore interface.
+func (s *MemoryIdempotencyStore) Release(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.records, key)
+
+	return nil
+}
+
+// FileIdempotencyStore keeps idempotency records in files, one per key, in the given directory.
+//
+// Keys are reserved by creating their files exclusively, so several processes may share the directory.
+// A key reserved by a process which crashed stays in progress until its file is removed.
+type FileIdempotencyStore struct {
+	dir string
+}
+
+type idempotencyFile struct {
+	Completed bool               `json:"completed"`
+	Record    *IdempotencyRecord `json:"record,omitempty"`
+}
+
+// NewFileIdempotencyStore creates a new FileIdempotencyStore instance, creating the directory if needed.
+func NewFileIdempotencyStore(dir string) (*FileIdempotencyStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("cannot create idempotency store: %w", err)
+	}
+
+	return &FileIdempotencyStore{dir: dir}, nil
+}
+
+// Begin implements IdempotencyStore interface.
+func (s *FileIdempotencyStore) Begin(_ context.Context, key string) (*IdempotencyRecord, error) {
+	f, err := os.OpenFile(s.path(key), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err == nil {
+		return nil, f.Close()
+	}
+
+	if !errors.Is(err, fs.ErrExist) {
+		return nil, fmt.Errorf("cannot reserve idempotency key: %w", err)
+	}
+
+	content, err := os.ReadFile(s.path(key))
+	if err != nil {
+		return nil, fmt.Errorf("cannot read idempotency record: %w", err)
+	}
+
+	// A reserved key has an empty file until it is completed.
+	var file idempotencyFile
+	if len(content) == 0 || json.Unmarshal(content, &file) != nil || !file.Completed {
+		return nil, fmt.Errorf("%w: %s", ErrIdempotencyInProgress, key)
+	}
+
+	return file.Record, nil
+}
+
+// Complete implements IdempotencyStore interface.
+func (s *FileIdempotencyStore) Complete(_ context.Context, key string, record IdempotencyRecord) error {
+	content, err := json.Marshal(idempotencyFile{Completed: true, Record: &record})
+	if err != nil {
+		return fmt.Errorf("cannot encode idempotency record: %w", err)
+	}
+
+	return writeFileAtomically(s.path(key), content)
+}
+
+// Release implements IdempotencyStore interface.
+func (s *FileIdempotencyStore) Release(_ context.Context, key string) error {
+	if err := os.Remove(s.path(key)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("cannot release idempotency key: %w", err)
+	}
+
+	return nil
+}
+
+func (s *FileIdempotencyStore) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// writeFileAtomically writes the content to a temporary file and renames it, so readers never see partial content.
+func writeFileAtomically(path string, content []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("cannot write %s: %w", path, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		_ = tmp.Close()
+
+		return fmt.Errorf("cannot write %s: %w", path, err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("cannot write %s: %w", path, err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("cannot write %s: %w", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,267 @@
+package interactor_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/interactor/v2"
+)
+
+func TestIdempotency(t *testing.T) {
+	t.Parallel()
+
+	stores := map[string]func(t *testing.T) interactor.IdempotencyStore{
+		"memory store": func(t *testing.T) interactor.IdempotencyStore {
+			return interactor.NewMemoryIdempotencyStore()
+		},
+		"file store": func(t *testing.T) interactor.IdempotencyStore {
+			store, err := interactor.NewFileIdempotencyStore(t.TempDir())
+			require.NoError(t, err)
+
+			return store
+		},
+	}
+
+	for name, newStore := range stores {
+		newStore := newStore
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			runIdempotencyTests(t, newStore)
+		})
+	}
+
+	t.Run("file store keeps the outcomes across instances", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		dir := t.TempDir()
+		useCase := &PlaceOrderUseCase{}
+
+		first, err := interactor.NewFileIdempotencyStore(dir)
+		require.NoError(t, err)
+
+		runner := interactor.Chain(interactor.MustAdapt(useCase), interactor.Idempotency(first))
+		require.NoError(t, runner(context.Background(), PlaceOrder{Key: "abc", OrderID: 1}, &PlaceOrderResponse{}))
+
+		second, err := interactor.NewFileIdempotencyStore(dir)
+		require.NoError(t, err)
+
+		runner = interactor.Chain(interactor.MustAdapt(useCase), interactor.Idempotency(second))
+
+		// act
+		var res PlaceOrderResponse
+		err = runner(context.Background(), PlaceOrder{Key: "abc", OrderID: 1}, &res)
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, 1, useCase.Placed())
+		assert.Equal(t, 1, res.OrderID)
+	})
+}
+
+func runIdempotencyTests(t *testing.T, newStore func(t *testing.T) interactor.IdempotencyStore) {
+	t.Helper()
+
+	t.Run("a repeated run replays the stored response", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		useCase := &PlaceOrderUseCase{}
+		runner := interactor.Chain(interactor.MustAdapt(useCase), interactor.Idempotency(newStore(t)))
+		require.NoError(t, runner(context.Background(), PlaceOrder{Key: "abc", OrderID: 1}, &PlaceOrderResponse{}))
+
+		// act
+		var res PlaceOrderResponse
+		err := runner(context.Background(), PlaceOrder{Key: "abc", OrderID: 1}, &res)
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, 1, useCase.Placed())
+		assert.Equal(t, 1, res.OrderID)
+	})
+
+	t.Run("a repeated run replays the stored error", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		useCase := &PlaceOrderUseCase{err: errSomeErr}
+		runner := interactor.Chain(interactor.MustAdapt(useCase), interactor.Idempotency(newStore(t)))
+		require.ErrorIs(t, runner(context.Background(), PlaceOrder{Key: "abc"}, &PlaceOrderResponse{}), errSomeErr)
+
+		// act
+		err := runner(context.Background(), PlaceOrder{Key: "abc"}, &PlaceOrderResponse{})
+
+		// assert
+		require.ErrorIs(t, err, interactor.ErrIdempotencyReplayed)
+		assert.Contains(t, err.Error(), errSomeErr.Error())
+		assert.Equal(t, 1, useCase.Placed())
+	})
+
+	t.Run("the key stored in the context takes precedence", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		useCase := &PlaceOrderUseCase{}
+		runner := interactor.Chain(interactor.MustAdapt(useCase), interactor.Idempotency(newStore(t)))
+		ctx := interactor.WithIdempotencyKey(context.Background(), "xyz")
+		require.NoError(t, runner(ctx, PlaceOrder{Key: "abc", OrderID: 1}, &PlaceOrderResponse{}))
+
+		// act
+		var res PlaceOrderResponse
+		err := runner(ctx, PlaceOrder{Key: "def", OrderID: 2}, &res)
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, 1, useCase.Placed())
+		assert.Equal(t, 1, res.OrderID)
+	})
+
+	t.Run("runs with different keys are not deduplicated", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		useCase := &PlaceOrderUseCase{}
+		runner := interactor.Chain(interactor.MustAdapt(useCase), interactor.Idempotency(newStore(t)))
+		require.NoError(t, runner(context.Background(), PlaceOrder{Key: "abc", OrderID: 1}, &PlaceOrderResponse{}))
+
+		// act
+		var res PlaceOrderResponse
+		err := runner(context.Background(), PlaceOrder{Key: "def", OrderID: 2}, &res)
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, 2, useCase.Placed())
+		assert.Equal(t, 2, res.OrderID)
+	})
+
+	t.Run("runs without a key are passed through", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		useCase := &PlaceOrderUseCase{}
+		runner := interactor.Chain(interactor.MustAdapt(useCase), interactor.Idempotency(newStore(t)))
+		require.NoError(t, runner(context.Background(), PlaceOrder{}, &PlaceOrderResponse{}))
+
+		// act
+		err := runner(context.Background(), PlaceOrder{}, &PlaceOrderResponse{})
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, 2, useCase.Placed())
+	})
+
+	t.Run("a concurrent duplicate is rejected", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		started, release := make(chan struct{}), make(chan struct{})
+		defer close(release)
+
+		runner := interactor.Chain(func(context.Context, interactor.Request, interactor.Response) error {
+			close(started)
+			<-release
+
+			return nil
+		}, interactor.Idempotency(newStore(t)))
+
+		go func() { _ = runner(context.Background(), PlaceOrder{Key: "abc"}, &PlaceOrderResponse{}) }()
+		<-started
+
+		// act
+		err := runner(context.Background(), PlaceOrder{Key: "abc"}, &PlaceOrderResponse{})
+
+		// assert
+		require.ErrorIs(t, err, interactor.ErrIdempotencyInProgress)
+	})
+
+	t.Run("a response losing data in JSON is rejected before the run", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		var runs int
+		runner := interactor.Chain(func(context.Context, interactor.Request, interactor.Response) error {
+			runs++
+
+			return nil
+		}, interactor.Idempotency(newStore(t)))
+
+		ctx := interactor.WithIdempotencyKey(context.Background(), "abc")
+
+		// act
+		err := runner(ctx, TestRequest{}, &TestResponse{})
+
+		// assert
+		require.ErrorIs(t, err, interactor.ErrResponseNotStorable)
+		assert.ErrorContains(t, err, "interactor_test.TestResponse.result")
+		assert.Zero(t, runs)
+	})
+
+	t.Run("a response encoding itself is accepted", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		runner := interactor.Chain(func(_ context.Context, _ interactor.Request, resp interactor.Response) error {
+			resp.(*StampResponse).At = time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+
+			return nil
+		}, interactor.Idempotency(newStore(t)))
+
+		ctx := interactor.WithIdempotencyKey(context.Background(), "abc")
+		require.NoError(t, runner(ctx, TestRequest{}, &StampResponse{}))
+
+		// act
+		var res StampResponse
+		err := runner(ctx, TestRequest{}, &res)
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC), res.At)
+	})
+
+	t.Run("a run failed with a context error can be retried", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		useCase := &PlaceOrderUseCase{err: context.DeadlineExceeded}
+		runner := interactor.Chain(interactor.MustAdapt(useCase), interactor.Idempotency(newStore(t)))
+		require.ErrorIs(t, runner(context.Background(), PlaceOrder{Key: "abc"}, &PlaceOrderResponse{}), context.DeadlineExceeded)
+
+		// act
+		err := runner(context.Background(), PlaceOrder{Key: "abc"}, &PlaceOrderResponse{})
+
+		// assert
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, 2, useCase.Placed())
+	})
+
+	t.Run("a panicking run releases the key", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		store := newStore(t)
+		panicking := interactor.Chain(
+			func(context.Context, interactor.Request, interactor.Response) error { panic("boom") },
+			interactor.Idempotency(store),
+		)
+		require.Panics(t, func() { _ = panicking(context.Background(), PlaceOrder{Key: "abc"}, &PlaceOrderResponse{}) })
+
+		useCase := &PlaceOrderUseCase{}
+		runner := interactor.Chain(interactor.MustAdapt(useCase), interactor.Idempotency(store))
+
+		// act
+		err := runner(context.Background(), PlaceOrder{Key: "abc"}, &PlaceOrderResponse{})
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, 1, useCase.Placed())
+	})
+}
+
+type StampResponse struct {
+	At time.Time `json:"at"`
+}
@@ -0,0 +1,153 @@
+package interactor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Initializer is implemented by use cases which need to acquire resources before they are run.
+type Initializer interface {
+	Init(ctx context.Context) error
+}
+
+// HealthChecker is implemented by use cases which can tell whether they are able to run.
+type HealthChecker interface {
+	HealthCheck(ctx context.Context) error
+}
+
+// Closer is implemented by use cases which own resources to be released.
+type Closer interface {
+	Close(ctx context.Context) error
+}
+
+// registeredUseCase is a use case registered with RegisterUseCase, kept for its lifecycle.
+type registeredUseCase struct {
+	reqType reflect.Type
+	useCase interface{}
+}
+
+// RegisterUseCase registers the given use case for the provided request type.
+//
+// The use case either implements UseCaseRunner or has a Run method accepted by Adapt.
+// Unlike Register, the dispatcher keeps the use case, so if it implements Initializer, HealthChecker or Closer,
+// it takes part in Start, Health and Close.
+func (d *Dispatcher) RegisterUseCase(request Request, useCase interface{}) error {
+	runner, ok := useCase.(UseCaseRunner)
+
+	var fn UseCaseRunnerFn
+	if ok {
+		fn = runner.Run
+	} else {
+		adapted, err := Adapt(useCase)
+		if err != nil {
+			return fmt.Errorf("cannot register use case for %T: %w", request, err)
+		}
+
+		fn = adapted
+	}
+
+	d.register(reflect.TypeOf(request), fn, useCase)
+
+	return nil
+}
+
+// Start initializes the use cases implementing Initializer in the order they were registered.
+//
+// It stops at the first failure. Call Close to release the resources of the use cases initialized by then.
+func (d *Dispatcher) Start(ctx context.Context) error {
+	var started []registeredUseCase
+
+	defer func() {
+		d.mu.Lock()
+		d.started = started
+		d.mu.Unlock()
+	}()
+
+	for _, registered := range d.registeredUseCases() {
+		if initializer, ok := registered.useCase.(Initializer); ok {
+			if err := initializer.Init(ctx); err != nil {
+				return fmt.Errorf("cannot init use case for %s: %w", registered.reqType, err)
+			}
+		}
+
+		started = append(started, registered)
+	}
+
+	return nil
+}
+
+// HealthReport holds the outcomes of the health checks per request type.
+type HealthReport map[reflect.Type]error
+
+// Healthy tells whether all the checks have passed.
+func (r HealthReport) Healthy() bool {
+	return r.Err() == nil
+}
+
+// Err joins the errors of the failed checks, ordered by request type name.
+func (r HealthReport) Err() error {
+	var errs []error
+
+	for reqType, err := range r {
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", reqType, err))
+		}
+	}
+
+	sort.Slice(errs, func(i, j int) bool {
+		return errs[i].Error() < errs[j].Error()
+	})
+
+	return errors.Join(errs...)
+}
+
+// Health checks the use cases implementing HealthChecker in the order they were registered.
+func (d *Dispatcher) Health(ctx context.Context) HealthReport {
+	report := make(HealthReport)
+
+	for _, registered := range d.registeredUseCases() {
+		if checker, ok := registered.useCase.(HealthChecker); ok {
+			report[registered.reqType] = checker.HealthCheck(ctx)
+		}
+	}
+
+	return report
+}
+
+// Close closes the use cases implementing Closer in the reverse order they were started in.
+//
+// Only the use cases Start has got to are closed: the ones it has initialized or which need no initialization.
+// The use case failed to initialize and the ones after it are not closed, nor are the use cases registered
+// after Start. All of them are closed even if some fail, the failures are joined into the returned error.
+// Call Shutdown first to let the running use cases complete.
+func (d *Dispatcher) Close(ctx context.Context) error {
+	d.mu.Lock()
+	started := d.started
+	d.started = nil
+	d.mu.Unlock()
+
+	var errs []error
+
+	for i := len(started) - 1; i >= 0; i-- {
+		closer, ok := started[i].useCase.(Closer)
+		if !ok {
+			continue
+		}
+
+		if err := closer.Close(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("cannot close use case for %s: %w", started[i].reqType, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (d *Dispatcher) registeredUseCases() []registeredUseCase {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return d.useCases
+}
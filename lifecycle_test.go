@@ -0,0 +1,226 @@
+package interactor_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/interactor/v2"
+)
+
+var errUnhealthy = errors.New("database is down")
+
+func TestDispatcherLifecycle(t *testing.T) {
+	t.Parallel()
+
+	t.Run("the use cases are initialized in the registration order", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		journal := &Journal{}
+
+		dispatcher := interactor.NewDispatcher()
+		require.NoError(t, dispatcher.RegisterUseCase(TestRequest{}, &ResourcefulUseCase{name: "first", journal: journal}))
+		require.NoError(t, dispatcher.RegisterUseCase(PlaceOrder{}, &PlaceOrderUseCase{}))
+		require.NoError(t, dispatcher.RegisterUseCase(GetUser{}, &ResourcefulUseCase{name: "second", journal: journal}))
+
+		// act
+		err := dispatcher.Start(context.Background())
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, []string{"init first", "init second"}, journal.entries())
+	})
+
+	t.Run("the initialization stops at the first failure", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		journal := &Journal{}
+
+		dispatcher := interactor.NewDispatcher()
+		require.NoError(t, dispatcher.RegisterUseCase(TestRequest{},
+			&ResourcefulUseCase{name: "first", journal: journal, initErr: errSomeErr}))
+		require.NoError(t, dispatcher.RegisterUseCase(GetUser{}, &ResourcefulUseCase{name: "second", journal: journal}))
+
+		// act
+		err := dispatcher.Start(context.Background())
+
+		// assert
+		require.ErrorIs(t, err, errSomeErr)
+		assert.ErrorContains(t, err, "interactor_test.TestRequest")
+		assert.Equal(t, []string{"init first"}, journal.entries())
+	})
+
+	t.Run("the use cases are closed in the reverse registration order", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		journal := &Journal{}
+
+		dispatcher := interactor.NewDispatcher()
+		require.NoError(t, dispatcher.RegisterUseCase(TestRequest{},
+			&ResourcefulUseCase{name: "first", journal: journal, closeErr: errSomeErr}))
+		require.NoError(t, dispatcher.RegisterUseCase(GetUser{}, &ResourcefulUseCase{name: "second", journal: journal}))
+		require.NoError(t, dispatcher.Start(context.Background()))
+
+		// act
+		err := dispatcher.Close(context.Background())
+
+		// assert
+		require.ErrorIs(t, err, errSomeErr)
+		assert.Equal(t, []string{"init first", "init second", "close second", "close first"}, journal.entries())
+	})
+
+	t.Run("only the use cases initialized before a failure are closed", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		journal := &Journal{}
+
+		dispatcher := interactor.NewDispatcher()
+		require.NoError(t, dispatcher.RegisterUseCase(TestRequest{}, &ResourcefulUseCase{name: "first", journal: journal}))
+		require.NoError(t, dispatcher.RegisterUseCase(GetUser{},
+			&ResourcefulUseCase{name: "second", journal: journal, initErr: errSomeErr}))
+		require.NoError(t, dispatcher.RegisterUseCase(PlaceOrder{}, &ResourcefulOrderUseCase{
+			ResourcefulUseCase: ResourcefulUseCase{name: "third", journal: journal},
+		}))
+		require.Error(t, dispatcher.Start(context.Background()))
+
+		// act
+		err := dispatcher.Close(context.Background())
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, []string{"init first", "init second", "close first"}, journal.entries())
+	})
+
+	t.Run("the use cases are not closed unless started", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		journal := &Journal{}
+
+		dispatcher := interactor.NewDispatcher()
+		require.NoError(t, dispatcher.RegisterUseCase(TestRequest{}, &ResourcefulUseCase{name: "first", journal: journal}))
+
+		// act
+		err := dispatcher.Close(context.Background())
+
+		// assert
+		require.NoError(t, err)
+		assert.Empty(t, journal.entries())
+	})
+
+	t.Run("the health is reported per request type", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		dispatcher := interactor.NewDispatcher()
+		require.NoError(t, dispatcher.RegisterUseCase(TestRequest{}, &ResourcefulUseCase{}))
+		require.NoError(t, dispatcher.RegisterUseCase(GetUser{}, &ResourcefulUseCase{healthErr: errUnhealthy}))
+		require.NoError(t, dispatcher.RegisterUseCase(PlaceOrder{}, &PlaceOrderUseCase{}))
+
+		// act
+		report := dispatcher.Health(context.Background())
+
+		// assert
+		assert.Equal(t, interactor.HealthReport{
+			reflect.TypeOf(TestRequest{}): nil,
+			reflect.TypeOf(GetUser{}):     errUnhealthy,
+		}, report)
+		assert.False(t, report.Healthy())
+		require.ErrorIs(t, report.Err(), errUnhealthy)
+		assert.EqualError(t, report.Err(), "interactor_test.GetUser: database is down")
+	})
+
+	t.Run("a use case replaced with Register no longer takes part in the lifecycle", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		journal := &Journal{}
+
+		dispatcher := interactor.NewDispatcher()
+		require.NoError(t, dispatcher.RegisterUseCase(TestRequest{}, &ResourcefulUseCase{name: "first", journal: journal}))
+		dispatcher.Register(TestRequest{}, succeedingRunner)
+
+		// act
+		require.NoError(t, dispatcher.Start(context.Background()))
+		require.NoError(t, dispatcher.Close(context.Background()))
+
+		// assert
+		assert.Empty(t, journal.entries())
+	})
+
+	t.Run("a registered use case is run", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		dispatcher := interactor.NewDispatcher()
+		require.NoError(t, dispatcher.RegisterUseCase(TestRequest{}, &ResourcefulUseCase{}))
+		require.NoError(t, dispatcher.RegisterUseCase(PlaceOrder{}, interactor.UseCaseRunnerFn(succeedingRunner)))
+
+		// act
+		var res TestResponse
+		err := dispatcher.Run(context.Background(), TestRequest{id: 123}, &res)
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, 123, res.result)
+		require.NoError(t, dispatcher.Run(context.Background(), PlaceOrder{}, &PlaceOrderResponse{}))
+	})
+
+	t.Run("a use case without a valid Run method is rejected", func(t *testing.T) {
+		t.Parallel()
+
+		err := interactor.NewDispatcher().RegisterUseCase(TestRequest{}, &Owner{})
+		require.ErrorIs(t, err, interactor.ErrUseCaseRunnerHasNoRunMethod)
+	})
+}
+
+// ResourcefulUseCase implements all the lifecycle interfaces.
+type ResourcefulUseCase struct {
+	name    string
+	journal *Journal
+
+	initErr   error
+	healthErr error
+	closeErr  error
+}
+
+func (uc *ResourcefulUseCase) Run(_ context.Context, req TestRequest, res *TestResponse) error {
+	res.result = req.id
+
+	return nil
+}
+
+func (uc *ResourcefulUseCase) Init(context.Context) error {
+	uc.journal.record("init " + uc.name)
+
+	return uc.initErr
+}
+
+func (uc *ResourcefulUseCase) HealthCheck(context.Context) error {
+	return uc.healthErr
+}
+
+func (uc *ResourcefulUseCase) Close(context.Context) error {
+	uc.journal.record("close " + uc.name)
+
+	return uc.closeErr
+}
+
+// ResourcefulOrderUseCase is a ResourcefulUseCase placing orders, so it can be registered for another request type.
+type ResourcefulOrderUseCase struct {
+	ResourcefulUseCase
+}
+
+func (uc *ResourcefulOrderUseCase) Run(_ context.Context, req PlaceOrder, res *PlaceOrderResponse) error {
+	res.OrderID = req.OrderID
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package interactor
+
+import (
+	"context"
+	"fmt"
+)
+
+// Middleware decorates a UseCaseRunnerFn with additional behaviour.
+type Middleware func(next UseCaseRunnerFn) UseCaseRunnerFn
+
+// Chain wraps the given runner with the provided middlewares.
+//
+// The first middleware is the outermost one, so it is the first to see the request:
+//
+//	interactor.Chain(runner, first, second) // first(second(runner))
+func Chain(runner UseCaseRunnerFn, middlewares ...Middleware) UseCaseRunnerFn {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		runner = middlewares[i](runner)
+	}
+
+	return runner
+}
+
+// KeyFunc derives a key from the request being run.
+//
+// It is used by middlewares which keep state per key, e.g. per request type, per tenant or per user.
+type KeyFunc func(ctx context.Context, req Request) string
+
+// RequestTypeKey is a KeyFunc which uses the request type as a key.
+func RequestTypeKey(_ context.Context, req Request) string {
+	return fmt.Sprintf("%T", req)
+}
+
+// ContextValueKey is a KeyFunc which uses the context value stored under the given key,
+// e.g. a tenant or a user ID.
+func ContextValueKey(key interface{}) KeyFunc {
+	return func(ctx context.Context, _ Request) string {
+		return fmt.Sprint(ctx.Value(key))
+	}
+}
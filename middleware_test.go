@@ -0,0 +1,113 @@
+package interactor_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/interactor/v2"
+)
+
+func TestChain(t *testing.T) {
+	t.Parallel()
+
+	t.Run("middlewares are applied in order, the first one being the outermost", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		var calls []string
+
+		runner := interactor.Chain(
+			func(ctx context.Context, req interactor.Request, resp interactor.Response) error {
+				calls = append(calls, "runner")
+
+				return nil
+			},
+			recordingMiddleware("first", &calls),
+			recordingMiddleware("second", &calls),
+		)
+
+		// act
+		err := runner(context.Background(), TestRequest{}, &TestResponse{})
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, []string{"first", "second", "runner"}, calls)
+	})
+
+	t.Run("without middlewares the runner is returned as is", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		runner := interactor.Chain(interactor.MustAdapt(ConcreteUseCase{}))
+
+		// act
+		var res TestResponse
+		err := runner(context.Background(), TestRequest{id: 123}, &res)
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, 123, res.result)
+	})
+}
+
+func TestDispatcherUse(t *testing.T) {
+	t.Parallel()
+
+	t.Run("middlewares are applied to every registered use case", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		var calls []string
+
+		dispatcher := interactor.NewDispatcher()
+		dispatcher.Register(TestRequest{}, interactor.MustAdapt(ConcreteUseCase{}))
+		dispatcher.Use(recordingMiddleware("first", &calls))
+		dispatcher.Use(recordingMiddleware("second", &calls))
+
+		// act
+		var res TestResponse
+		err := dispatcher.Run(context.Background(), TestRequest{id: 123}, &res)
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, []string{"first", "second"}, calls)
+		assert.Equal(t, 123, res.result)
+	})
+
+	t.Run("middlewares are not applied when use case not found", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		var calls []string
+
+		dispatcher := interactor.NewDispatcher()
+		dispatcher.Use(recordingMiddleware("first", &calls))
+
+		// act
+		err := dispatcher.Run(context.Background(), TestRequest{}, &TestResponse{})
+
+		// assert
+		assertUseCaseRunnerNotFound(t, err)
+		assert.Empty(t, calls)
+	})
+}
+
+func TestRequestTypeKey(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "interactor_test.TestRequest", interactor.RequestTypeKey(context.Background(), TestRequest{}))
+	assert.Equal(t, "*interactor_test.TestRequest", interactor.RequestTypeKey(context.Background(), &TestRequest{}))
+}
+
+func recordingMiddleware(name string, calls *[]string) interactor.Middleware {
+	return func(next interactor.UseCaseRunnerFn) interactor.UseCaseRunnerFn {
+		return func(ctx context.Context, req interactor.Request, resp interactor.Response) error {
+			*calls = append(*calls, name)
+
+			return next(ctx, req, resp)
+		}
+	}
+}
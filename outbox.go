@@ -0,0 +1,354 @@
+package interactor
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// OutboxMessage is a request recorded to be dispatched once the transaction it is recorded in is committed.
+type OutboxMessage struct {
+	ID        string
+	Type      string
+	Payload   json.RawMessage
+	CreatedAt time.Time
+}
+
+// OutboxStore stores outbox messages.
+type OutboxStore interface {
+	// Add stores the message within the given transaction, so it is only visible once the transaction is committed.
+	Add(ctx context.Context, tx *sql.Tx, msg OutboxMessage) error
+	// Pending returns up to limit messages, the oldest first, leaving out the ones with the given IDs.
+	Pending(ctx context.Context, limit int, exclude []string) ([]OutboxMessage, error)
+	// Delete removes the dispatched message.
+	Delete(ctx context.Context, id string) error
+}
+
+// Outbox records follow-up requests in the same transaction as the writes of a use case,
+// so they are dispatched if and only if the transaction is committed. See OutboxRelay.
+//
+// The requests are serialized with a TypeRegistry, so their types must be registered there.
+type Outbox struct {
+	store    OutboxStore
+	registry *TypeRegistry
+	now      func() time.Time
+}
+
+// NewOutbox creates a new Outbox instance.
+func NewOutbox(store OutboxStore, registry *TypeRegistry) *Outbox {
+	return &Outbox{store: store, registry: registry, now: time.Now}
+}
+
+// Record records the request to be dispatched after the transaction of the context is committed.
+//
+// It returns ErrNoTransaction if the context carries no transaction, see Transactional,
+// and ErrTypeNotRegistered if the request type is not registered.
+func (o *Outbox) Record(ctx context.Context, req Request) error {
+	tx, ok := TxFromContext(ctx)
+	if !ok {
+		return ErrNoTransaction
+	}
+
+	name, payload, err := o.registry.Encode(req)
+	if err != nil {
+		return fmt.Errorf("cannot record to outbox: %w", err)
+	}
+
+	msg := OutboxMessage{ID: newID(), Type: name, Payload: payload, CreatedAt: o.now()}
+	if err := o.store.Add(ctx, tx, msg); err != nil {
+		return fmt.Errorf("cannot record to outbox: %w", err)
+	}
+
+	return nil
+}
+
+const (
+	defaultRelayBatchSize    = 100
+	defaultRelayPollInterval = time.Second
+	defaultRelayMaxAttempts  = 10
+)
+
+// OutboxRelayOption configures an OutboxRelay.
+type OutboxRelayOption func(*OutboxRelay)
+
+// WithRelayBatchSize sets the maximum number of messages read from the store at once. It defaults to 100.
+func WithRelayBatchSize(size int) OutboxRelayOption {
+	return func(r *OutboxRelay) {
+		r.batchSize = size
+	}
+}
+
+// WithRelayPollInterval sets how often the store is checked for new messages. It defaults to 1 second.
+func WithRelayPollInterval(interval time.Duration) OutboxRelayOption {
+	return func(r *OutboxRelay) {
+		r.pollInterval = interval
+	}
+}
+
+// WithRelayRetryPolicy sets how many times a failing message is dispatched before it is moved
+// to the dead-letter store and how long to wait between the dispatches.
+//
+// By default, a message is dispatched up to 10 times with an exponential backoff from 1 second up to 1 minute.
+func WithRelayRetryPolicy(policy RetryPolicy) OutboxRelayOption {
+	return func(r *OutboxRelay) {
+		r.retryPolicy = policy
+	}
+}
+
+// WithRelayDeadLetterStore sets the store the messages are moved to once their retry policy is exhausted.
+//
+// The messages are stored as jobs with the attempts made to dispatch them.
+// By default, dead letters are kept in memory, see MemoryDeadLetterStore.
+func WithRelayDeadLetterStore(store DeadLetterStore) OutboxRelayOption {
+	return func(r *OutboxRelay) {
+		r.deadLetters = store
+	}
+}
+
+// WithRelayErrorHandler sets the function the failures of messages and of the store are reported to.
+//
+// The message is zero if the failure is not related to a particular message.
+// The failure of a message moved to the dead-letter store is reported as ErrMessageDeadLettered.
+func WithRelayErrorHandler(handler func(msg OutboxMessage, err error)) OutboxRelayOption {
+	return func(r *OutboxRelay) {
+		r.onError = handler
+	}
+}
+
+// OutboxRelay reads the outbox and dispatches the recorded requests.
+//
+// The requests are dispatched at least once: a message is deleted only after its request succeeds,
+// so it is dispatched again if it fails or if the relay stops in between. The message ID is set as
+// the idempotency key of the dispatch, so the consumers using the Idempotency middleware run it only once.
+// The failures of these dispatches are not stored by Idempotency, so a failed message is run again when retried.
+//
+// A failing message is retried according to the retry policy and then moved to the dead-letter store,
+// so it does not hold up the messages recorded after it. A message whose consumer panics fails with ErrUseCasePanicked.
+type OutboxRelay struct {
+	store        OutboxStore
+	registry     *TypeRegistry
+	dispatcher   *Dispatcher
+	batchSize    int
+	pollInterval time.Duration
+	retryPolicy  RetryPolicy
+	deadLetters  DeadLetterStore
+	now          func() time.Time
+	onError      func(msg OutboxMessage, err error)
+
+	mu       sync.Mutex
+	failures map[string]*relayFailure
+}
+
+// relayFailure tracks the failed attempts to dispatch a message.
+type relayFailure struct {
+	attempts []JobAttempt
+	retryAt  time.Time
+}
+
+// NewOutboxRelay creates a new OutboxRelay instance which dispatches the messages through the given dispatcher.
+func NewOutboxRelay(
+	store OutboxStore, registry *TypeRegistry, dispatcher *Dispatcher, opts ...OutboxRelayOption,
+) *OutboxRelay {
+	r := &OutboxRelay{
+		store:        store,
+		registry:     registry,
+		dispatcher:   dispatcher,
+		batchSize:    defaultRelayBatchSize,
+		pollInterval: defaultRelayPollInterval,
+		retryPolicy: RetryPolicy{
+			MaxAttempts: defaultRelayMaxAttempts,
+			Backoff:     ExponentialBackoff(time.Second, time.Minute),
+		},
+		deadLetters: NewMemoryDeadLetterStore(),
+		now:         time.Now,
+		onError:     func(OutboxMessage, error) {},
+		failures:    make(map[string]*relayFailure),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// Run relays the messages until the context is done and returns the context error.
+func (r *OutboxRelay) Run(ctx context.Context) error {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		more, _, err := r.relayPending(ctx)
+		if err != nil {
+			r.onError(OutboxMessage{}, err)
+		}
+
+		if more {
+			timer.Reset(0)
+		} else {
+			timer.Reset(r.pollInterval)
+		}
+	}
+}
+
+// RelayPending dispatches a batch of pending messages and returns the number of messages removed from the outbox,
+// either dispatched or moved to the dead-letter store.
+//
+// The messages are dispatched in the order they were recorded. A failed message is reported to the error handler
+// and left in the outbox to be retried once its backoff elapses. The messages waiting for their backoff are left out
+// of the batch, so they do not hold up the messages recorded after them.
+func (r *OutboxRelay) RelayPending(ctx context.Context) (int, error) {
+	_, removed, err := r.relayPending(ctx)
+
+	return removed, err
+}
+
+// relayPending relays a batch of pending messages. Besides the number of the removed messages, it tells whether
+// more messages may be waiting: the batch is full and the next one differs from it, as some of its messages
+// are removed or backing off now.
+func (r *OutboxRelay) relayPending(ctx context.Context) (bool, int, error) {
+	msgs, err := r.store.Pending(ctx, r.batchSize, r.backingOffIDs())
+	if err != nil {
+		return false, 0, fmt.Errorf("cannot read outbox: %w", err)
+	}
+
+	removed, backingOff := 0, 0
+
+	for _, msg := range msgs {
+		if ctx.Err() != nil {
+			return false, removed, ctx.Err()
+		}
+
+		ok, err := r.relay(ctx, msg)
+		if err != nil {
+			r.onError(msg, err)
+		}
+
+		switch {
+		case ok:
+			removed++
+		case r.backingOff(msg.ID):
+			backingOff++
+		}
+	}
+
+	more := len(msgs) == r.batchSize && removed+backingOff > 0
+
+	return more, removed, nil
+}
+
+// relay dispatches the message and tells whether it has been removed from the outbox.
+func (r *OutboxRelay) relay(ctx context.Context, msg OutboxMessage) (bool, error) {
+	startedAt := r.now()
+
+	req, resp, err := r.registry.Decode(msg.Type, msg.Payload)
+	if err != nil {
+		// The message cannot ever be dispatched, so it is not retried.
+		return r.fail(ctx, msg, JobAttempt{StartedAt: startedAt, FinishedAt: r.now(), Error: err.Error()}, err, true)
+	}
+
+	dispatchCtx := withRetriedFailures(WithIdempotencyKey(ctx, msg.ID))
+	if err := runRecovered(dispatchCtx, r.dispatcher.Run, req, resp); err != nil {
+		if ctx.Err() != nil {
+			// The relay is stopping, so the attempt does not count.
+			return false, err
+		}
+
+		return r.fail(ctx, msg, JobAttempt{StartedAt: startedAt, FinishedAt: r.now(), Error: err.Error()}, err, false)
+	}
+
+	r.forget(msg.ID)
+
+	if err := r.store.Delete(ctx, msg.ID); err != nil {
+		return false, fmt.Errorf("cannot delete dispatched message: %w", err)
+	}
+
+	return true, nil
+}
+
+// fail either schedules the failed message to be retried or moves it to the dead-letter store.
+func (r *OutboxRelay) fail(
+	ctx context.Context, msg OutboxMessage, attempt JobAttempt, err error, permanent bool,
+) (bool, error) {
+	r.mu.Lock()
+	failure, ok := r.failures[msg.ID]
+	if !ok {
+		failure = &relayFailure{}
+		r.failures[msg.ID] = failure
+	}
+
+	failure.attempts = append(failure.attempts, attempt)
+	failure.retryAt = attempt.FinishedAt.Add(r.retryPolicy.delay(len(failure.attempts)))
+	attempts := append([]JobAttempt(nil), failure.attempts...)
+	r.mu.Unlock()
+
+	if !permanent && len(attempts) < r.retryPolicy.MaxAttempts {
+		return false, err
+	}
+
+	job := Job{
+		ID:         msg.ID,
+		Type:       msg.Type,
+		Payload:    msg.Payload,
+		RunAt:      msg.CreatedAt,
+		EnqueuedAt: msg.CreatedAt,
+		Attempts:   attempts,
+	}
+	deadLetter := DeadLetter{Job: job, Error: err.Error(), DeadLetteredAt: attempt.FinishedAt}
+
+	if addErr := r.deadLetters.Add(ctx, deadLetter); addErr != nil {
+		// The message is kept in the outbox, so it is not lost.
+		return false, errors.Join(err, fmt.Errorf("cannot move the message to the dead-letter store: %w", addErr))
+	}
+
+	r.forget(msg.ID)
+
+	if delErr := r.store.Delete(ctx, msg.ID); delErr != nil {
+		return false, errors.Join(err, fmt.Errorf("cannot delete dead-lettered message: %w", delErr))
+	}
+
+	return true, fmt.Errorf("%w: %w", ErrMessageDeadLettered, err)
+}
+
+// backingOffIDs returns the IDs of the messages waiting for their backoff to elapse.
+func (r *OutboxRelay) backingOffIDs() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+
+	var ids []string
+
+	for id, failure := range r.failures {
+		if now.Before(failure.retryAt) {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
+
+func (r *OutboxRelay) backingOff(id string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	failure, ok := r.failures[id]
+
+	return ok && r.now().Before(failure.retryAt)
+}
+
+func (r *OutboxRelay) forget(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.failures, id)
+}
@@ -0,0 +1,141 @@
+package interactor
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+const defaultOutboxTable = "outbox"
+
+// SQLOutboxStoreOption configures a SQLOutboxStore.
+type SQLOutboxStoreOption func(*SQLOutboxStore)
+
+// WithOutboxTable sets the name of the outbox table. It defaults to "outbox".
+func WithOutboxTable(table string) SQLOutboxStoreOption {
+	return func(s *SQLOutboxStore) {
+		s.table = table
+	}
+}
+
+// WithOutboxPlaceholder sets the function returning the query placeholder of the n-th argument, starting from 1.
+//
+// By default, "?" is used. See DollarPlaceholder for PostgreSQL.
+func WithOutboxPlaceholder(placeholder func(n int) string) SQLOutboxStoreOption {
+	return func(s *SQLOutboxStore) {
+		s.placeholder = placeholder
+	}
+}
+
+// DollarPlaceholder returns PostgreSQL style placeholders: $1, $2 and so on.
+func DollarPlaceholder(n int) string {
+	return fmt.Sprintf("$%d", n)
+}
+
+// SQLOutboxStore keeps outbox messages in a database table with the following columns:
+//
+//	CREATE TABLE outbox (
+//		id         VARCHAR(32) PRIMARY KEY,
+//		type       VARCHAR(255) NOT NULL,
+//		payload    BLOB NOT NULL,
+//		created_at TIMESTAMP NOT NULL
+//	);
+type SQLOutboxStore struct {
+	db          *sql.DB
+	table       string
+	placeholder func(n int) string
+}
+
+// NewSQLOutboxStore creates a new SQLOutboxStore instance.
+func NewSQLOutboxStore(db *sql.DB, opts ...SQLOutboxStoreOption) *SQLOutboxStore {
+	s := &SQLOutboxStore{
+		db:          db,
+		table:       defaultOutboxTable,
+		placeholder: func(int) string { return "?" },
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Add implements OutboxStore interface.
+func (s *SQLOutboxStore) Add(ctx context.Context, tx *sql.Tx, msg OutboxMessage) error {
+	query := fmt.Sprintf(
+		"INSERT INTO %s (id, type, payload, created_at) VALUES (%s)", s.table, s.placeholders(4),
+	)
+
+	if _, err := tx.ExecContext(ctx, query, msg.ID, msg.Type, []byte(msg.Payload), msg.CreatedAt); err != nil {
+		return fmt.Errorf("cannot add outbox message: %w", err)
+	}
+
+	return nil
+}
+
+// Pending implements OutboxStore interface.
+func (s *SQLOutboxStore) Pending(ctx context.Context, limit int, exclude []string) ([]OutboxMessage, error) {
+	var where string
+
+	args := make([]interface{}, len(exclude))
+	if len(exclude) > 0 {
+		where = fmt.Sprintf(" WHERE id NOT IN (%s)", s.placeholders(len(exclude)))
+
+		for i, id := range exclude {
+			args[i] = id
+		}
+	}
+
+	query := fmt.Sprintf(
+		"SELECT id, type, payload, created_at FROM %s%s ORDER BY created_at, id LIMIT %d", s.table, where, limit,
+	)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read outbox messages: %w", err)
+	}
+	defer rows.Close()
+
+	var msgs []OutboxMessage
+
+	for rows.Next() {
+		var (
+			msg     OutboxMessage
+			payload []byte
+		)
+
+		if err := rows.Scan(&msg.ID, &msg.Type, &payload, &msg.CreatedAt); err != nil {
+			return nil, fmt.Errorf("cannot read outbox messages: %w", err)
+		}
+
+		msg.Payload = payload
+		msgs = append(msgs, msg)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("cannot read outbox messages: %w", err)
+	}
+
+	return msgs, nil
+}
+
+// Delete implements OutboxStore interface.
+func (s *SQLOutboxStore) Delete(ctx context.Context, id string) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = %s", s.table, s.placeholder(1))
+
+	if _, err := s.db.ExecContext(ctx, query, id); err != nil {
+		return fmt.Errorf("cannot delete outbox message: %w", err)
+	}
+
+	return nil
+}
+
+func (s *SQLOutboxStore) placeholders(n int) string {
+	placeholders := make([]string, n)
+	for i := range placeholders {
+		placeholders[i] = s.placeholder(i + 1)
+	}
+
+	return strings.Join(placeholders, ", ")
+}
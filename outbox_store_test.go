@@ -0,0 +1,112 @@
+package interactor_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/interactor/v2"
+)
+
+func TestSQLOutboxStore(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("the pending messages are read up to the limit", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		ctx := context.Background()
+		db := (&FakeDB{}).Open()
+		store := interactor.NewSQLOutboxStore(db)
+
+		msgs := []interactor.OutboxMessage{
+			{ID: "1", Type: "place_order", Payload: []byte(`{"order_id":1}`), CreatedAt: now},
+			{ID: "2", Type: "place_order", Payload: []byte(`{"order_id":2}`), CreatedAt: now.Add(time.Second)},
+			{ID: "3", Type: "place_order", Payload: []byte(`{"order_id":3}`), CreatedAt: now.Add(2 * time.Second)},
+		}
+
+		tx, err := db.BeginTx(ctx, nil)
+		require.NoError(t, err)
+
+		for _, msg := range msgs {
+			require.NoError(t, store.Add(ctx, tx, msg))
+		}
+
+		require.NoError(t, tx.Commit())
+
+		// act
+		pending, err := store.Pending(ctx, 2, nil)
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, msgs[:2], pending)
+	})
+
+	t.Run("the excluded messages are left out", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		ctx := context.Background()
+		db := (&FakeDB{}).Open()
+		store := interactor.NewSQLOutboxStore(db)
+
+		msgs := []interactor.OutboxMessage{
+			{ID: "1", Type: "place_order", Payload: []byte(`{"order_id":1}`), CreatedAt: now},
+			{ID: "2", Type: "place_order", Payload: []byte(`{"order_id":2}`), CreatedAt: now.Add(time.Second)},
+			{ID: "3", Type: "place_order", Payload: []byte(`{"order_id":3}`), CreatedAt: now.Add(2 * time.Second)},
+		}
+
+		tx, err := db.BeginTx(ctx, nil)
+		require.NoError(t, err)
+
+		for _, msg := range msgs {
+			require.NoError(t, store.Add(ctx, tx, msg))
+		}
+
+		require.NoError(t, tx.Commit())
+
+		// act
+		pending, err := store.Pending(ctx, 2, []string{"1"})
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, msgs[1:], pending)
+	})
+
+	t.Run("the table and the placeholders are configurable", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		ctx := context.Background()
+		fake := &FakeDB{}
+		db := fake.Open()
+		store := interactor.NewSQLOutboxStore(db,
+			interactor.WithOutboxTable("events"), interactor.WithOutboxPlaceholder(interactor.DollarPlaceholder))
+
+		tx, err := db.BeginTx(ctx, nil)
+		require.NoError(t, err)
+
+		require.NoError(t, store.Add(ctx, tx, interactor.OutboxMessage{ID: "1", CreatedAt: now}))
+		require.NoError(t, tx.Commit())
+
+		// act
+		_, err = store.Pending(ctx, 10, []string{"2", "3"})
+		require.NoError(t, err)
+
+		err = store.Delete(ctx, "1")
+
+		// assert
+		require.NoError(t, err)
+		assert.Zero(t, fake.Len())
+		assert.Equal(t, []string{
+			"INSERT INTO events (id, type, payload, created_at) VALUES ($1, $2, $3, $4)",
+			"SELECT id, type, payload, created_at FROM events WHERE id NOT IN ($1, $2) ORDER BY created_at, id LIMIT 10",
+			"DELETE FROM events WHERE id = $1",
+		}, fake.Queries())
+	})
+}
@@ -0,0 +1,443 @@
+package interactor_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/interactor/v2"
+)
+
+func TestOutbox(t *testing.T) {
+	t.Parallel()
+
+	t.Run("a recorded request is stored when the transaction is committed", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		fake := &FakeDB{}
+		db := fake.Open()
+		outbox := interactor.NewOutbox(interactor.NewSQLOutboxStore(db), newTestRegistry(t))
+
+		runner := interactor.Chain(recordingToOutbox(outbox, 123), interactor.Transactional(db, nil))
+
+		// act
+		err := runner(context.Background(), TestRequest{}, &TestResponse{})
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, 1, fake.Len())
+	})
+
+	t.Run("a recorded request is discarded when the transaction is rolled back", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		fake := &FakeDB{}
+		db := fake.Open()
+		outbox := interactor.NewOutbox(interactor.NewSQLOutboxStore(db), newTestRegistry(t))
+
+		runner := interactor.Chain(
+			func(ctx context.Context, req interactor.Request, resp interactor.Response) error {
+				require.NoError(t, recordingToOutbox(outbox, 123)(ctx, req, resp))
+
+				return errSomeErr
+			},
+			interactor.Transactional(db, nil),
+		)
+
+		// act
+		err := runner(context.Background(), TestRequest{}, &TestResponse{})
+
+		// assert
+		require.ErrorIs(t, err, errSomeErr)
+		assert.Zero(t, fake.Len())
+	})
+
+	t.Run("a request cannot be recorded outside of a transaction", func(t *testing.T) {
+		t.Parallel()
+
+		outbox := interactor.NewOutbox(interactor.NewSQLOutboxStore((&FakeDB{}).Open()), newTestRegistry(t))
+
+		err := outbox.Record(context.Background(), PlaceOrder{})
+		require.ErrorIs(t, err, interactor.ErrNoTransaction)
+	})
+
+	t.Run("a request of an unregistered type cannot be recorded", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		db := (&FakeDB{}).Open()
+		outbox := interactor.NewOutbox(interactor.NewSQLOutboxStore(db), newTestRegistry(t))
+
+		tx, err := db.BeginTx(context.Background(), nil)
+		require.NoError(t, err)
+
+		defer func() { _ = tx.Rollback() }()
+
+		// act
+		err = outbox.Record(interactor.WithTx(context.Background(), tx), DeleteUser{})
+
+		// assert
+		require.ErrorIs(t, err, interactor.ErrTypeNotRegistered)
+	})
+}
+
+func TestOutboxRelay(t *testing.T) {
+	t.Parallel()
+
+	t.Run("the recorded requests are dispatched in order and removed", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		fake := &FakeDB{}
+		store := recordToOutbox(t, fake, 1, 2, 3)
+
+		orders := &OrderLog{}
+		relay := interactor.NewOutboxRelay(store, newTestRegistry(t), orders.dispatcher())
+
+		// act
+		n, err := relay.RelayPending(context.Background())
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, 3, n)
+		assert.Equal(t, []int{1, 2, 3}, orders.IDs())
+		assert.Zero(t, fake.Len())
+	})
+
+	t.Run("a failed request is kept and does not block the others", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		fake := &FakeDB{}
+		store := recordToOutbox(t, fake, 1, 2)
+
+		orders := &OrderLog{}
+		placeOrder := orders.runner()
+
+		dispatcher := interactor.NewDispatcher()
+		dispatcher.Register(PlaceOrder{}, func(ctx context.Context, req interactor.Request, resp interactor.Response) error {
+			if req.(PlaceOrder).OrderID == 1 {
+				return errSomeErr
+			}
+
+			return placeOrder(ctx, req, resp)
+		})
+
+		var failures []error
+		relay := interactor.NewOutboxRelay(store, newTestRegistry(t), dispatcher,
+			interactor.WithRelayErrorHandler(func(_ interactor.OutboxMessage, err error) {
+				failures = append(failures, err)
+			}),
+		)
+
+		// act
+		_, err := relay.RelayPending(context.Background())
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, []int{2}, orders.IDs())
+		assert.Equal(t, 1, fake.Len())
+		require.Len(t, failures, 1)
+		require.ErrorIs(t, failures[0], errSomeErr)
+	})
+
+	t.Run("a redelivered request is deduplicated by the consumer", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		fake := &FakeDB{}
+		store := recordToOutbox(t, fake, 1)
+
+		orders := &OrderLog{}
+		dispatcher := orders.dispatcher()
+		dispatcher.Use(interactor.Idempotency(interactor.NewMemoryIdempotencyStore()))
+
+		relay := interactor.NewOutboxRelay(store, newTestRegistry(t), dispatcher)
+
+		fake.setFailDeletes(true)
+		_, err := relay.RelayPending(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, 1, fake.Len())
+
+		fake.setFailDeletes(false)
+
+		// act
+		_, err = relay.RelayPending(context.Background())
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, []int{1}, orders.IDs())
+		assert.Zero(t, fake.Len())
+	})
+
+	t.Run("a failing message is dead-lettered and does not hold up the following ones", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		fake := &FakeDB{}
+		store := recordToOutbox(t, fake, 1, 2)
+
+		orders := &OrderLog{}
+		deadLetters := interactor.NewMemoryDeadLetterStore()
+
+		var failures int32
+		relay := interactor.NewOutboxRelay(store, newTestRegistry(t), failingOrderDispatcher(orders, 1, -1),
+			interactor.WithRelayBatchSize(1),
+			interactor.WithRelayPollInterval(tick),
+			interactor.WithRelayRetryPolicy(interactor.RetryPolicy{MaxAttempts: 3}),
+			interactor.WithRelayDeadLetterStore(deadLetters),
+			interactor.WithRelayErrorHandler(func(interactor.OutboxMessage, error) { atomic.AddInt32(&failures, 1) }),
+		)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		// act
+		go func() { _ = relay.Run(ctx) }()
+
+		// assert
+		require.Eventually(t, func() bool { return len(orders.IDs()) == 1 }, waitFor, tick)
+		assert.Equal(t, []int{2}, orders.IDs())
+		assert.EqualValues(t, 3, atomic.LoadInt32(&failures))
+
+		dead, err := deadLetters.List(context.Background())
+		require.NoError(t, err)
+		require.Len(t, dead, 1)
+		assert.JSONEq(t, `{"key":"","order_id":1}`, string(dead[0].Job.Payload))
+		assert.Len(t, dead[0].Job.Attempts, 3)
+		assert.Eventually(t, func() bool { return fake.Len() == 0 }, waitFor, tick)
+	})
+
+	t.Run("a panicking consumer fails the message instead of crashing the relay", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		fake := &FakeDB{}
+		store := recordToOutbox(t, fake, 1)
+
+		dispatcher := interactor.NewDispatcher()
+		dispatcher.Register(PlaceOrder{}, func(context.Context, interactor.Request, interactor.Response) error {
+			panic("boom")
+		})
+
+		var failures []error
+		relay := interactor.NewOutboxRelay(store, newTestRegistry(t), dispatcher,
+			interactor.WithRelayRetryPolicy(interactor.RetryPolicy{MaxAttempts: 3}),
+			interactor.WithRelayErrorHandler(func(_ interactor.OutboxMessage, err error) {
+				failures = append(failures, err)
+			}),
+		)
+
+		// act
+		n, err := relay.RelayPending(context.Background())
+
+		// assert
+		require.NoError(t, err)
+		assert.Zero(t, n)
+		assert.Equal(t, 1, fake.Len())
+		require.Len(t, failures, 1)
+		require.ErrorIs(t, failures[0], interactor.ErrUseCasePanicked)
+	})
+
+	t.Run("a message waiting for its backoff is skipped", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		fake := &FakeDB{}
+		store := recordToOutbox(t, fake, 1, 2)
+
+		orders := &OrderLog{}
+		relay := interactor.NewOutboxRelay(store, newTestRegistry(t), failingOrderDispatcher(orders, 1, -1),
+			interactor.WithRelayRetryPolicy(interactor.RetryPolicy{
+				MaxAttempts: 3,
+				Backoff:     func(int) time.Duration { return time.Hour },
+			}),
+		)
+
+		n, err := relay.RelayPending(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, 1, n)
+
+		// act
+		n, err = relay.RelayPending(context.Background())
+
+		// assert
+		require.NoError(t, err)
+		assert.Zero(t, n)
+		assert.Equal(t, []int{2}, orders.IDs())
+		assert.Equal(t, 1, fake.Len())
+	})
+
+	t.Run("messages waiting for their backoff do not hold up the following ones", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		fake := &FakeDB{}
+		store := recordToOutbox(t, fake, 1, 2, 3)
+
+		orders := &OrderLog{}
+		placeOrder := orders.runner()
+
+		dispatcher := interactor.NewDispatcher()
+		dispatcher.Register(PlaceOrder{}, func(ctx context.Context, req interactor.Request, resp interactor.Response) error {
+			if req.(PlaceOrder).OrderID != 3 {
+				return errSomeErr
+			}
+
+			return placeOrder(ctx, req, resp)
+		})
+
+		relay := interactor.NewOutboxRelay(store, newTestRegistry(t), dispatcher,
+			interactor.WithRelayBatchSize(2),
+			interactor.WithRelayRetryPolicy(interactor.RetryPolicy{
+				MaxAttempts: 3,
+				Backoff:     func(int) time.Duration { return time.Hour },
+			}),
+		)
+
+		_, err := relay.RelayPending(context.Background())
+		require.NoError(t, err)
+
+		// act
+		n, err := relay.RelayPending(context.Background())
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, 1, n)
+		assert.Equal(t, []int{3}, orders.IDs())
+		assert.Equal(t, 2, fake.Len())
+	})
+
+	t.Run("a full batch of failed messages does not delay the following ones", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		fake := &FakeDB{}
+		store := recordToOutbox(t, fake, 1, 2)
+
+		orders := &OrderLog{}
+		relay := interactor.NewOutboxRelay(store, newTestRegistry(t), failingOrderDispatcher(orders, 1, -1),
+			interactor.WithRelayBatchSize(1),
+			interactor.WithRelayPollInterval(time.Hour),
+			interactor.WithRelayRetryPolicy(interactor.RetryPolicy{
+				MaxAttempts: 3,
+				Backoff:     func(int) time.Duration { return time.Hour },
+			}),
+		)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		// act
+		go func() { _ = relay.Run(ctx) }()
+
+		// assert
+		assert.Eventually(t, func() bool { return len(orders.IDs()) == 1 }, waitFor, tick)
+		assert.Equal(t, []int{2}, orders.IDs())
+	})
+
+	t.Run("a retried message is run again by the consumer deduplicating requests", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		fake := &FakeDB{}
+		store := recordToOutbox(t, fake, 1)
+
+		orders := &OrderLog{}
+		dispatcher := failingOrderDispatcher(orders, 1, 1)
+		dispatcher.Use(interactor.Idempotency(interactor.NewMemoryIdempotencyStore()))
+
+		relay := interactor.NewOutboxRelay(store, newTestRegistry(t), dispatcher,
+			interactor.WithRelayRetryPolicy(interactor.RetryPolicy{MaxAttempts: 3}),
+		)
+
+		_, err := relay.RelayPending(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, 1, fake.Len())
+
+		// act
+		n, err := relay.RelayPending(context.Background())
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, 1, n)
+		assert.Equal(t, []int{1}, orders.IDs())
+		assert.Zero(t, fake.Len())
+	})
+
+	t.Run("the relay runs until the context is done", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		fake := &FakeDB{}
+		store := recordToOutbox(t, fake, 1, 2, 3)
+
+		orders := &OrderLog{}
+		relay := interactor.NewOutboxRelay(store, newTestRegistry(t), orders.dispatcher(),
+			interactor.WithRelayBatchSize(2), interactor.WithRelayPollInterval(tick))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+
+		// act
+		go func() { done <- relay.Run(ctx) }()
+
+		// assert
+		assert.Eventually(t, func() bool { return len(orders.IDs()) == 3 }, waitFor, tick)
+
+		cancel()
+		require.ErrorIs(t, <-done, context.Canceled)
+	})
+}
+
+// failingOrderDispatcher places orders, except the one with the given ID, which fails the given number of times
+// or always if it is negative.
+func failingOrderDispatcher(orders *OrderLog, orderID, times int) *interactor.Dispatcher {
+	placeOrder := orders.runner()
+
+	var failures int32
+
+	dispatcher := interactor.NewDispatcher()
+	dispatcher.Register(PlaceOrder{}, func(ctx context.Context, req interactor.Request, resp interactor.Response) error {
+		if req.(PlaceOrder).OrderID == orderID && (times < 0 || atomic.AddInt32(&failures, 1) <= int32(times)) {
+			return errSomeErr
+		}
+
+		return placeOrder(ctx, req, resp)
+	})
+
+	return dispatcher
+}
+
+// recordToOutbox records orders with the given IDs to the outbox backed by the fake database.
+func recordToOutbox(t *testing.T, fake *FakeDB, orderIDs ...int) interactor.OutboxStore {
+	t.Helper()
+
+	db := fake.Open()
+	store := interactor.NewSQLOutboxStore(db)
+	outbox := interactor.NewOutbox(store, newTestRegistry(t))
+
+	runner := interactor.Chain(recordingToOutbox(outbox, orderIDs...), interactor.Transactional(db, nil))
+	require.NoError(t, runner(context.Background(), TestRequest{}, &TestResponse{}))
+
+	return store
+}
+
+// recordingToOutbox records PlaceOrder requests with the given order IDs to the outbox.
+func recordingToOutbox(outbox *interactor.Outbox, orderIDs ...int) interactor.UseCaseRunnerFn {
+	return func(ctx context.Context, _ interactor.Request, _ interactor.Response) error {
+		for _, id := range orderIDs {
+			if err := outbox.Record(ctx, PlaceOrder{OrderID: id}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,124 @@
+package interactor
+
+import (
+	"context"
+	"fmt"
+)
+
+// PipelineStep is a use case run as a part of a Pipeline.
+type PipelineStep struct {
+	name        string
+	runner      UseCaseRunner
+	newResponse func() Response
+}
+
+// Step defines a pipeline step which writes its result to a response of type Resp.
+//
+//	interactor.Step[UserResponse]("load user", interactor.MustAdapt(loadUser))
+func Step[Resp any](name string, runner UseCaseRunner) PipelineStep {
+	return PipelineStep{
+		name:        name,
+		runner:      runner,
+		newResponse: func() Response { return new(Resp) },
+	}
+}
+
+// Mapper maps the response of a pipeline step into the request of the next one.
+type Mapper func(ctx context.Context, resp Response) (Request, error)
+
+// MapTo adapts a typed mapping function into a Mapper.
+//
+//	interactor.MapTo(func(ctx context.Context, user *UserResponse) (GetOrders, error) {
+//		return GetOrders{UserID: user.ID}, nil
+//	})
+func MapTo[From Response, To Request](fn func(ctx context.Context, resp From) (To, error)) Mapper {
+	return func(ctx context.Context, resp Response) (Request, error) {
+		from, ok := resp.(From)
+		if !ok {
+			var want From
+
+			return nil, fmt.Errorf("%w: want %T, got %T", ErrResultTypeMismatch, want, resp)
+		}
+
+		return fn(ctx, from)
+	}
+}
+
+// PipelineStepError reports the pipeline step which failed.
+type PipelineStepError struct {
+	// Step is the name of the failed step.
+	Step string
+	// Index is the position of the failed step in the pipeline, starting from zero.
+	Index int
+	// Err is the error the step failed with.
+	Err error
+}
+
+func (e *PipelineStepError) Error() string {
+	return fmt.Sprintf("pipeline step #%d %q failed: %v", e.Index, e.Step, e.Err)
+}
+
+func (e *PipelineStepError) Unwrap() error {
+	return e.Err
+}
+
+// Pipeline runs use cases one after another, mapping the response of each step into the request of the next one.
+//
+// The first step gets the request the pipeline is run with and the last step writes its result to the
+// response the pipeline is run with:
+//
+//	pipeline := interactor.NewPipeline(interactor.Step[UserResponse]("load user", loadUser)).
+//		Then(toGetOrders, interactor.Step[OrdersResponse]("load orders", loadOrders))
+//
+//	dispatcher.Register(GetUser{}, pipeline.Runner())
+type Pipeline struct {
+	steps   []PipelineStep
+	mappers []Mapper
+}
+
+// NewPipeline creates a new Pipeline starting with the given step.
+func NewPipeline(first PipelineStep) *Pipeline {
+	return &Pipeline{steps: []PipelineStep{first}}
+}
+
+// Then adds the next step to the pipeline, which gets its request from the given mapper.
+func (p *Pipeline) Then(mapper Mapper, next PipelineStep) *Pipeline {
+	p.steps = append(p.steps, next)
+	p.mappers = append(p.mappers, mapper)
+
+	return p
+}
+
+// Runner returns the pipeline as a single use case runner.
+//
+// A failure of any step stops the pipeline and is reported as *PipelineStepError.
+func (p *Pipeline) Runner() UseCaseRunnerFn {
+	steps := append([]PipelineStep(nil), p.steps...)
+	mappers := append([]Mapper(nil), p.mappers...)
+
+	return func(ctx context.Context, req Request, resp Response) error {
+		var prev Response
+
+		for i, step := range steps {
+			if i > 0 {
+				var err error
+				if req, err = mappers[i-1](ctx, prev); err != nil {
+					return &PipelineStepError{Step: step.name, Index: i, Err: fmt.Errorf("cannot map request: %w", err)}
+				}
+			}
+
+			stepResp := resp
+			if i < len(steps)-1 {
+				stepResp = step.newResponse()
+			}
+
+			if err := step.runner.Run(ctx, req, stepResp); err != nil {
+				return &PipelineStepError{Step: step.name, Index: i, Err: err}
+			}
+
+			prev = stepResp
+		}
+
+		return nil
+	}
+}
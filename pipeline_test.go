@@ -0,0 +1,113 @@
+package interactor_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/interactor/v2"
+)
+
+var errMapping = errors.New("mapping error")
+
+func TestPipeline(t *testing.T) {
+	t.Parallel()
+
+	toGetUser := interactor.MapTo(func(_ context.Context, res *TestResponse) (GetUser, error) {
+		return GetUser{UserID: fmt.Sprint(res.result)}, nil
+	})
+
+	t.Run("the response of each step is mapped into the request of the next one", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		pipeline := interactor.NewPipeline(interactor.Step[TestResponse]("load id", interactor.MustAdapt(ConcreteUseCase{}))).
+			Then(toGetUser, interactor.Step[GetUserResponse]("load user", interactor.MustAdapt(GetUserUseCase{})))
+
+		dispatcher := interactor.NewDispatcher()
+		dispatcher.Register(TestRequest{}, pipeline.Runner())
+
+		// act
+		var res GetUserResponse
+		err := dispatcher.Run(context.Background(), TestRequest{id: 123}, &res)
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, "user 123", res.Name)
+	})
+
+	t.Run("a single step pipeline runs the step", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		pipeline := interactor.NewPipeline(interactor.Step[TestResponse]("load id", interactor.MustAdapt(ConcreteUseCase{})))
+
+		// act
+		var res TestResponse
+		err := pipeline.Runner()(context.Background(), TestRequest{id: 123}, &res)
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, 123, res.result)
+	})
+
+	t.Run("the failed step is reported", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		pipeline := interactor.NewPipeline(interactor.Step[TestResponse]("load id", interactor.MustAdapt(ConcreteUseCase{}))).
+			Then(toGetUser, interactor.Step[GetUserResponse]("load user", failingRunner(errSomeErr)))
+
+		// act
+		err := pipeline.Runner()(context.Background(), TestRequest{}, &GetUserResponse{})
+
+		// assert
+		require.ErrorIs(t, err, errSomeErr)
+
+		var stepErr *interactor.PipelineStepError
+		require.True(t, errors.As(err, &stepErr))
+		assert.Equal(t, "load user", stepErr.Step)
+		assert.Equal(t, 1, stepErr.Index)
+		assert.Equal(t, `pipeline step #1 "load user" failed: some error`, err.Error())
+	})
+
+	t.Run("the failed mapping is reported", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		failingMapper := interactor.MapTo(func(context.Context, *TestResponse) (GetUser, error) {
+			return GetUser{}, errMapping
+		})
+
+		pipeline := interactor.NewPipeline(interactor.Step[TestResponse]("load id", interactor.MustAdapt(ConcreteUseCase{}))).
+			Then(failingMapper, interactor.Step[GetUserResponse]("load user", interactor.MustAdapt(GetUserUseCase{})))
+
+		// act
+		err := pipeline.Runner()(context.Background(), TestRequest{}, &GetUserResponse{})
+
+		// assert
+		require.ErrorIs(t, err, errMapping)
+
+		var stepErr *interactor.PipelineStepError
+		require.True(t, errors.As(err, &stepErr))
+		assert.Equal(t, "load user", stepErr.Step)
+	})
+
+	t.Run("a mapper must get the response type it expects", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		pipeline := interactor.NewPipeline(interactor.Step[GetUserResponse]("load user", interactor.MustAdapt(GetUserUseCase{}))).
+			Then(toGetUser, interactor.Step[GetUserResponse]("load user again", interactor.MustAdapt(GetUserUseCase{})))
+
+		// act
+		err := pipeline.Runner()(context.Background(), GetUser{}, &GetUserResponse{})
+
+		// assert
+		require.ErrorIs(t, err, interactor.ErrResultTypeMismatch)
+	})
+}
@@ -0,0 +1,133 @@
+package interactor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Notification is an interface representing a fact several modules may react to.
+type Notification interface{}
+
+// NotificationHandler reacts to a notification.
+type NotificationHandler interface {
+	Handle(ctx context.Context, n Notification) error
+}
+
+// NotificationHandlerFn allows using pure functions as a NotificationHandler.
+type NotificationHandlerFn func(ctx context.Context, n Notification) error
+
+// Handle reacts to the given notification.
+func (fn NotificationHandlerFn) Handle(ctx context.Context, n Notification) error {
+	return fn(ctx, n)
+}
+
+// Handle converts a function accepting a concrete notification type into a NotificationHandlerFn.
+//
+// The handler fails with ErrNotificationTypeMismatch if it is given a notification of another type.
+func Handle[N Notification](fn func(ctx context.Context, n N) error) NotificationHandlerFn {
+	return func(ctx context.Context, n Notification) error {
+		typed, ok := n.(N)
+		if !ok {
+			return fmt.Errorf("%w: %T", ErrNotificationTypeMismatch, n)
+		}
+
+		return fn(ctx, typed)
+	}
+}
+
+// PublisherOption configures a Publisher.
+type PublisherOption func(*Publisher)
+
+// WithParallelDelivery makes the publisher run the handlers of a notification concurrently.
+//
+// By default, the handlers are run one by one in the order they are subscribed.
+func WithParallelDelivery() PublisherOption {
+	return func(p *Publisher) {
+		p.parallel = true
+	}
+}
+
+// Publisher delivers notifications to all the handlers subscribed to their types.
+//
+// Unlike Dispatcher, which runs exactly one use case per request type, any number of handlers may be subscribed
+// to the same notification type. It is safe to subscribe handlers concurrently with publishing notifications.
+type Publisher struct {
+	mu       sync.RWMutex
+	handlers map[reflect.Type][]NotificationHandlerFn
+	parallel bool
+}
+
+// NewPublisher creates a new Publisher instance.
+func NewPublisher(opts ...PublisherOption) *Publisher {
+	p := &Publisher{handlers: make(map[reflect.Type][]NotificationHandlerFn)}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// Subscribe subscribes the given handlers to the provided notification type.
+func (p *Publisher) Subscribe(n Notification, handlers ...NotificationHandlerFn) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	notificationType := reflect.TypeOf(n)
+	p.handlers[notificationType] = appendCopy(p.handlers[notificationType], handlers...)
+}
+
+// Publish delivers the notification to all the handlers subscribed to its type.
+//
+// It returns nil if there are no subscribers. A failing or panicking handler does not prevent the others from
+// being run: all of them are run and their failures are joined into the returned error.
+// A panic is reported as ErrNotificationHandlerPanicked.
+func (p *Publisher) Publish(ctx context.Context, n Notification) error {
+	p.mu.RLock()
+	handlers := p.handlers[reflect.TypeOf(n)]
+	p.mu.RUnlock()
+
+	errs := make([]error, len(handlers))
+
+	if !p.parallel {
+		for i, handler := range handlers {
+			errs[i] = recovered(ErrNotificationHandlerPanicked, func() error {
+				return handler(ctx, n)
+			})
+		}
+
+		return joinHandlerErrors(n, errs)
+	}
+
+	var wg sync.WaitGroup
+
+	for i, handler := range handlers {
+		wg.Add(1)
+
+		go func(i int, handler NotificationHandlerFn) {
+			defer wg.Done()
+
+			errs[i] = recovered(ErrNotificationHandlerPanicked, func() error {
+				return handler(ctx, n)
+			})
+		}(i, handler)
+	}
+
+	wg.Wait()
+
+	return joinHandlerErrors(n, errs)
+}
+
+func joinHandlerErrors(n Notification, errs []error) error {
+	var failed []error
+
+	for i, err := range errs {
+		if err != nil {
+			failed = append(failed, fmt.Errorf("handler #%d of %T failed: %w", i, n, err))
+		}
+	}
+
+	return errors.Join(failed...)
+}
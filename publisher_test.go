@@ -0,0 +1,191 @@
+package interactor_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/interactor/v2"
+)
+
+type OrderPlaced struct {
+	OrderID int
+}
+
+type OrderCancelled struct {
+	OrderID int
+}
+
+func TestPublisher(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name string
+		opts []interactor.PublisherOption
+	}{
+		{name: "sequential delivery"},
+		{name: "parallel delivery", opts: []interactor.PublisherOption{interactor.WithParallelDelivery()}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			t.Run("a notification is delivered to all the subscribers of its type", func(t *testing.T) {
+				t.Parallel()
+
+				// arrange
+				journal := &Journal{}
+
+				publisher := interactor.NewPublisher(tc.opts...)
+				publisher.Subscribe(OrderPlaced{}, journal.handler("billing", nil), journal.handler("shipping", nil))
+				publisher.Subscribe(OrderCancelled{}, journal.handler("refunds", nil))
+
+				// act
+				err := publisher.Publish(context.Background(), OrderPlaced{OrderID: 123})
+
+				// assert
+				require.NoError(t, err)
+				assert.ElementsMatch(t, []string{"billing", "shipping"}, journal.entries())
+			})
+
+			t.Run("a failing handler does not prevent the others from being run", func(t *testing.T) {
+				t.Parallel()
+
+				// arrange
+				journal := &Journal{}
+
+				publisher := interactor.NewPublisher(tc.opts...)
+				publisher.Subscribe(OrderPlaced{},
+					journal.handler("billing", errSomeErr),
+					journal.handler("shipping", nil),
+					journal.handler("analytics", errNotOwner),
+				)
+
+				// act
+				err := publisher.Publish(context.Background(), OrderPlaced{OrderID: 123})
+
+				// assert
+				require.ErrorIs(t, err, errSomeErr)
+				require.ErrorIs(t, err, errNotOwner)
+				assert.ElementsMatch(t, []string{"billing", "shipping", "analytics"}, journal.entries())
+			})
+
+			t.Run("a panicking handler is isolated", func(t *testing.T) {
+				t.Parallel()
+
+				// arrange
+				journal := &Journal{}
+
+				publisher := interactor.NewPublisher(tc.opts...)
+				publisher.Subscribe(OrderPlaced{},
+					func(context.Context, interactor.Notification) error { panic("boom") },
+					journal.handler("shipping", nil),
+				)
+
+				// act
+				err := publisher.Publish(context.Background(), OrderPlaced{OrderID: 123})
+
+				// assert
+				require.ErrorIs(t, err, interactor.ErrNotificationHandlerPanicked)
+				assert.ErrorContains(t, err, "boom")
+				assert.Equal(t, []string{"shipping"}, journal.entries())
+			})
+		})
+	}
+
+	t.Run("the handlers are run in the subscription order by default", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		journal := &Journal{}
+
+		publisher := interactor.NewPublisher()
+		publisher.Subscribe(OrderPlaced{}, journal.handler("billing", nil))
+		publisher.Subscribe(OrderPlaced{}, journal.handler("shipping", errSomeErr))
+		publisher.Subscribe(OrderPlaced{}, journal.handler("analytics", nil))
+
+		// act
+		err := publisher.Publish(context.Background(), OrderPlaced{OrderID: 123})
+
+		// assert
+		assert.EqualError(t, err, "handler #1 of interactor_test.OrderPlaced failed: some error")
+		assert.Equal(t, []string{"billing", "shipping", "analytics"}, journal.entries())
+	})
+
+	t.Run("a notification with no subscribers is dropped", func(t *testing.T) {
+		t.Parallel()
+
+		err := interactor.NewPublisher().Publish(context.Background(), OrderPlaced{})
+		assert.NoError(t, err)
+	})
+
+	t.Run("the handlers are run concurrently with parallel delivery", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		var started sync.WaitGroup
+
+		started.Add(2)
+
+		waitForOthers := func(context.Context, interactor.Notification) error {
+			started.Done()
+			started.Wait()
+
+			return nil
+		}
+
+		publisher := interactor.NewPublisher(interactor.WithParallelDelivery())
+		publisher.Subscribe(OrderPlaced{}, waitForOthers, waitForOthers)
+
+		// act
+		err := publisher.Publish(context.Background(), OrderPlaced{})
+
+		// assert
+		require.NoError(t, err)
+	})
+}
+
+func TestHandle(t *testing.T) {
+	t.Parallel()
+
+	t.Run("the handler is given the typed notification", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		var got OrderPlaced
+
+		handler := interactor.Handle(func(_ context.Context, n OrderPlaced) error {
+			got = n
+
+			return nil
+		})
+
+		// act
+		err := handler(context.Background(), OrderPlaced{OrderID: 123})
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, OrderPlaced{OrderID: 123}, got)
+	})
+
+	t.Run("a notification of another type is rejected", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		handler := interactor.Handle(func(context.Context, OrderPlaced) error {
+			return nil
+		})
+
+		// act
+		err := handler(context.Background(), OrderCancelled{})
+
+		// assert
+		require.ErrorIs(t, err, interactor.ErrNotificationTypeMismatch)
+	})
+}
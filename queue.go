@@ -0,0 +1,440 @@
+package interactor
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Job is a request enqueued to be run later.
+type Job struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	Payload    json.RawMessage `json:"payload"`
+	Priority   int             `json:"priority"`
+	RunAt      time.Time       `json:"run_at"`
+	EnqueuedAt time.Time       `json:"enqueued_at"`
+	// Attempts holds the failed runs of the job.
+	Attempts []JobAttempt `json:"attempts,omitempty"`
+}
+
+// JobAttempt describes a failed run of a job.
+type JobAttempt struct {
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	Error      string    `json:"error"`
+}
+
+// JobStore stores enqueued jobs.
+type JobStore interface {
+	// Save stores a new job or replaces the stored one with the same ID. The job becomes available to be claimed.
+	Save(ctx context.Context, job Job) error
+	// Claim takes the due job with the highest priority, so that no other worker takes it.
+	// Jobs of the same priority are claimed in the order they are due.
+	// It returns false if no job is due.
+	Claim(ctx context.Context, now time.Time) (Job, bool, error)
+	// Complete removes the claimed job.
+	Complete(ctx context.Context, id string) error
+}
+
+const (
+	defaultQueueWorkers      = 1
+	defaultQueuePollInterval = time.Second
+)
+
+// QueueOption configures a Queue.
+type QueueOption func(*Queue)
+
+// WithQueueStore sets the store the jobs are kept in.
+//
+// By default, jobs are kept in memory, see MemoryJobStore.
+func WithQueueStore(store JobStore) QueueOption {
+	return func(q *Queue) {
+		q.store = store
+	}
+}
+
+// WithQueueWorkers sets the number of jobs run concurrently. It defaults to 1.
+func WithQueueWorkers(workers int) QueueOption {
+	return func(q *Queue) {
+		q.workers = workers
+	}
+}
+
+// WithQueuePollInterval sets how often idle workers check the store for due jobs. It defaults to 1 second.
+//
+// Workers are woken up right away when a job is enqueued, so the interval only matters for delayed jobs
+// and jobs stored by other processes.
+func WithQueuePollInterval(interval time.Duration) QueueOption {
+	return func(q *Queue) {
+		q.pollInterval = interval
+	}
+}
+
+// WithQueueRetryPolicy sets how failing jobs are retried.
+//
+// By default, failing jobs are not retried.
+func WithQueueRetryPolicy(policy RetryPolicy) QueueOption {
+	return func(q *Queue) {
+		q.retryPolicy = policy
+	}
+}
+
+// WithDeadLetterStore sets the store the jobs are moved to once their retry policy is exhausted.
+//
+// By default, dead letters are kept in memory, see MemoryDeadLetterStore. If a job cannot be added to the store,
+// it is kept in the job store and run again after the backoff, or the poll interval if it is longer.
+func WithDeadLetterStore(store DeadLetterStore) QueueOption {
+	return func(q *Queue) {
+		q.deadLetters = store
+	}
+}
+
+// WithQueueClock sets the function used to get the current time.
+func WithQueueClock(now func() time.Time) QueueOption {
+	return func(q *Queue) {
+		q.now = now
+	}
+}
+
+// WithQueueErrorHandler sets the function the failures of jobs and of the store are reported to.
+//
+// The job is zero if the failure is not related to a particular job.
+// The failure of a job moved to the dead-letter store is reported as ErrJobDeadLettered.
+func WithQueueErrorHandler(handler func(job Job, err error)) QueueOption {
+	return func(q *Queue) {
+		q.onError = handler
+	}
+}
+
+// EnqueueOption configures an enqueued job.
+type EnqueueOption func(*Job)
+
+// WithJobDelay delays the job by the given duration.
+func WithJobDelay(delay time.Duration) EnqueueOption {
+	return func(job *Job) {
+		job.RunAt = job.RunAt.Add(delay)
+	}
+}
+
+// WithJobPriority sets the priority of the job. Jobs with higher priorities are run first. It defaults to 0.
+func WithJobPriority(priority int) EnqueueOption {
+	return func(job *Job) {
+		job.Priority = priority
+	}
+}
+
+// Queue runs requests in the background through a Dispatcher.
+//
+// The requests are serialized with a TypeRegistry, so their types must be registered there.
+// With a persistent store, e.g. FileJobStore, the jobs survive restarts.
+//
+// Failing jobs are retried according to the retry policy. Once it is exhausted, they are moved
+// to the dead-letter store, where they can be inspected and requeued. A job whose use case panics
+// fails with ErrUseCasePanicked.
+type Queue struct {
+	dispatcher   *Dispatcher
+	registry     *TypeRegistry
+	store        JobStore
+	deadLetters  DeadLetterStore
+	retryPolicy  RetryPolicy
+	workers      int
+	pollInterval time.Duration
+	now          func() time.Time
+	onError      func(job Job, err error)
+
+	wake chan struct{}
+	stop chan struct{}
+	wg   sync.WaitGroup
+
+	// interrupt is closed when the shutdown times out to cancel the contexts of the running jobs.
+	interrupt chan struct{}
+
+	mu          sync.Mutex
+	started     bool
+	closed      bool
+	interrupted bool
+}
+
+// NewQueue creates a new Queue instance which runs jobs through the given dispatcher.
+//
+// The workers are not started until Start is called, but jobs may be enqueued before that.
+func NewQueue(dispatcher *Dispatcher, registry *TypeRegistry, opts ...QueueOption) *Queue {
+	q := &Queue{
+		dispatcher:   dispatcher,
+		registry:     registry,
+		store:        NewMemoryJobStore(),
+		deadLetters:  NewMemoryDeadLetterStore(),
+		workers:      defaultQueueWorkers,
+		pollInterval: defaultQueuePollInterval,
+		now:          time.Now,
+		onError:      func(Job, error) {},
+		wake:         make(chan struct{}, 1),
+		stop:         make(chan struct{}),
+		interrupt:    make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(q)
+	}
+
+	return q
+}
+
+// Enqueue stores the request to be run in the background and returns the ID of the job.
+//
+// It returns ErrTypeNotRegistered if the request type is not registered and ErrQueueClosed if the queue is shut down.
+func (q *Queue) Enqueue(ctx context.Context, req Request, opts ...EnqueueOption) (string, error) {
+	if q.isClosed() {
+		return "", ErrQueueClosed
+	}
+
+	name, payload, err := q.registry.Encode(req)
+	if err != nil {
+		return "", fmt.Errorf("cannot enqueue: %w", err)
+	}
+
+	now := q.now()
+	job := Job{ID: newID(), Type: name, Payload: payload, RunAt: now, EnqueuedAt: now}
+
+	for _, opt := range opts {
+		opt(&job)
+	}
+
+	if err := q.store.Save(ctx, job); err != nil {
+		return "", fmt.Errorf("cannot enqueue: %w", err)
+	}
+
+	q.notify()
+
+	return job.ID, nil
+}
+
+// Start starts the workers.
+//
+// It does nothing if the queue is already started and returns ErrQueueClosed if it is shut down.
+func (q *Queue) Start() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return ErrQueueClosed
+	}
+
+	if q.started {
+		return nil
+	}
+
+	q.started = true
+
+	for i := 0; i < q.workers; i++ {
+		q.wg.Add(1)
+
+		go q.work()
+	}
+
+	return nil
+}
+
+// Shutdown stops the workers from claiming new jobs and waits for the running ones to complete.
+//
+// If the context is done first, the contexts of the running jobs are cancelled and the context error is returned
+// without waiting for them. The jobs interrupted this way are put back into the store, so they are run again
+// once the queue is restarted.
+func (q *Queue) Shutdown(ctx context.Context) error {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.stop)
+	}
+	q.mu.Unlock()
+
+	done := make(chan struct{})
+
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		q.mu.Lock()
+		if !q.interrupted {
+			q.interrupted = true
+			close(q.interrupt)
+		}
+		q.mu.Unlock()
+
+		return ctx.Err()
+	}
+}
+
+func (q *Queue) isClosed() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.closed
+}
+
+// notify wakes up an idle worker, if any.
+func (q *Queue) notify() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (q *Queue) work() {
+	defer q.wg.Done()
+
+	timer := time.NewTimer(q.pollInterval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-q.stop:
+			return
+		default:
+		}
+
+		job, ok, err := q.store.Claim(context.Background(), q.now())
+		if err != nil {
+			q.onError(Job{}, fmt.Errorf("cannot claim a job: %w", err))
+		}
+
+		if ok {
+			q.process(job)
+
+			continue
+		}
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+
+		timer.Reset(q.pollInterval)
+
+		select {
+		case <-q.stop:
+			return
+		case <-q.wake:
+		case <-timer.C:
+		}
+	}
+}
+
+func (q *Queue) process(job Job) {
+	ctx := context.Background()
+
+	req, resp, err := q.registry.Decode(job.Type, job.Payload)
+	if err != nil {
+		// The job cannot ever succeed, so it is not retried.
+		q.fail(job, JobAttempt{StartedAt: q.now(), FinishedAt: q.now(), Error: err.Error()}, err, true)
+
+		return
+	}
+
+	startedAt := q.now()
+
+	runCtx, cancel := contextUntil(q.interrupt)
+	defer cancel()
+
+	err = runRecovered(runCtx, q.dispatcher.Run, req, resp)
+	if err != nil && runCtx.Err() != nil {
+		// The job was interrupted by the shutdown, so it is kept to be run again.
+		if err := q.store.Save(ctx, job); err != nil {
+			q.onError(job, fmt.Errorf("cannot put the interrupted job back: %w", err))
+		}
+
+		return
+	}
+
+	if err != nil {
+		q.fail(job, JobAttempt{StartedAt: startedAt, FinishedAt: q.now(), Error: err.Error()}, err, false)
+
+		return
+	}
+
+	if err := q.store.Complete(ctx, job.ID); err != nil {
+		q.onError(job, fmt.Errorf("cannot complete the job: %w", err))
+	}
+}
+
+// fail either schedules the failed job to be retried or moves it to the dead-letter store.
+func (q *Queue) fail(job Job, attempt JobAttempt, err error, permanent bool) {
+	ctx := context.Background()
+
+	job.Attempts = append(job.Attempts, attempt)
+
+	if !permanent && len(job.Attempts) < q.retryPolicy.MaxAttempts {
+		job.RunAt = attempt.FinishedAt.Add(q.retryPolicy.delay(len(job.Attempts)))
+		if err := q.store.Save(ctx, job); err != nil {
+			q.onError(job, fmt.Errorf("cannot schedule the job to be retried: %w", err))
+		}
+
+		q.onError(job, err)
+
+		return
+	}
+
+	deadLetter := DeadLetter{Job: job, Error: err.Error(), DeadLetteredAt: attempt.FinishedAt}
+	if addErr := q.deadLetters.Add(ctx, deadLetter); addErr != nil {
+		// The job is kept instead of being lost, so it is run again later and dead-lettered if it fails once more.
+		job.RunAt = attempt.FinishedAt.Add(q.deadLetterRetryDelay(len(job.Attempts)))
+		if err := q.store.Save(ctx, job); err != nil {
+			q.onError(job, fmt.Errorf("cannot keep the job failed to be dead-lettered: %w", err))
+		}
+
+		q.onError(job, fmt.Errorf("cannot move the job to the dead-letter store: %w", addErr))
+		q.onError(job, err)
+
+		return
+	}
+
+	if err := q.store.Complete(ctx, job.ID); err != nil {
+		q.onError(job, fmt.Errorf("cannot complete the job: %w", err))
+	}
+
+	q.onError(job, fmt.Errorf("%w: %w", ErrJobDeadLettered, err))
+}
+
+// deadLetterRetryDelay returns the delay before running again a job which could not be dead-lettered.
+func (q *Queue) deadLetterRetryDelay(attempt int) time.Duration {
+	if delay := q.retryPolicy.delay(attempt); delay > q.pollInterval {
+		return delay
+	}
+
+	return q.pollInterval
+}
+
+// contextUntil returns a context which is cancelled when the given channel is closed or the cancel func is called.
+func contextUntil(done <-chan struct{}) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		select {
+		case <-done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, cancel
+}
+
+func newID() string {
+	var id [16]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		panic(fmt.Sprintf("cannot generate ID: %v", err))
+	}
+
+	return hex.EncodeToString(id[:])
+}
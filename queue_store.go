@@ -0,0 +1,189 @@
+package interactor
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryJobStore keeps jobs in memory.
+type MemoryJobStore struct {
+	mu      sync.Mutex
+	jobs    map[string]*storedJob
+	lastSeq uint64
+}
+
+type storedJob struct {
+	job     Job
+	seq     uint64
+	claimed bool
+}
+
+// NewMemoryJobStore creates a new MemoryJobStore instance.
+func NewMemoryJobStore() *MemoryJobStore {
+	return &MemoryJobStore{jobs: make(map[string]*storedJob)}
+}
+
+// Save implements JobStore interface.
+func (s *MemoryJobStore) Save(_ context.Context, job Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastSeq++
+	s.jobs[job.ID] = &storedJob{job: job, seq: s.lastSeq}
+
+	return nil
+}
+
+// Claim implements JobStore interface.
+func (s *MemoryJobStore) Claim(_ context.Context, now time.Time) (Job, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var next *storedJob
+
+	for _, stored := range s.jobs {
+		if stored.claimed || stored.job.RunAt.After(now) {
+			continue
+		}
+
+		if next == nil || runsBefore(stored, next) {
+			next = stored
+		}
+	}
+
+	if next == nil {
+		return Job{}, false, nil
+	}
+
+	next.claimed = true
+
+	return next.job, true, nil
+}
+
+// Complete implements JobStore interface.
+func (s *MemoryJobStore) Complete(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.jobs, id)
+
+	return nil
+}
+
+// Len returns the number of stored jobs, including the claimed ones.
+func (s *MemoryJobStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.jobs)
+}
+
+func runsBefore(a, b *storedJob) bool {
+	if a.job.Priority != b.job.Priority {
+		return a.job.Priority > b.job.Priority
+	}
+
+	if !a.job.RunAt.Equal(b.job.RunAt) {
+		return a.job.RunAt.Before(b.job.RunAt)
+	}
+
+	return a.seq < b.seq
+}
+
+// FileJobStore keeps jobs in files, one per job, in the given directory.
+//
+// The jobs are loaded when the store is created, so the directory must not be shared by several processes.
+// The jobs claimed but not completed before a crash are run again after the restart.
+type FileJobStore struct {
+	dir string
+	mem *MemoryJobStore
+}
+
+// NewFileJobStore creates a new FileJobStore instance, creating the directory if needed,
+// and loads the jobs stored there.
+func NewFileJobStore(dir string) (*FileJobStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("cannot create job store: %w", err)
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("cannot load jobs: %w", err)
+	}
+
+	jobs := make([]Job, 0, len(paths))
+
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load jobs: %w", err)
+		}
+
+		var job Job
+		if err := json.Unmarshal(content, &job); err != nil {
+			return nil, fmt.Errorf("cannot load job from %s: %w", path, err)
+		}
+
+		jobs = append(jobs, job)
+	}
+
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].EnqueuedAt.Before(jobs[j].EnqueuedAt)
+	})
+
+	s := &FileJobStore{dir: dir, mem: NewMemoryJobStore()}
+	for _, job := range jobs {
+		_ = s.mem.Save(context.Background(), job)
+	}
+
+	return s, nil
+}
+
+// Save implements JobStore interface.
+func (s *FileJobStore) Save(ctx context.Context, job Job) error {
+	content, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("cannot encode job: %w", err)
+	}
+
+	if err := writeFileAtomically(s.path(job.ID), content); err != nil {
+		return err
+	}
+
+	return s.mem.Save(ctx, job)
+}
+
+// Claim implements JobStore interface.
+func (s *FileJobStore) Claim(ctx context.Context, now time.Time) (Job, bool, error) {
+	return s.mem.Claim(ctx, now)
+}
+
+// Complete implements JobStore interface.
+func (s *FileJobStore) Complete(ctx context.Context, id string) error {
+	if err := os.Remove(s.path(id)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("cannot complete job: %w", err)
+	}
+
+	return s.mem.Complete(ctx, id)
+}
+
+// Len returns the number of stored jobs, including the claimed ones.
+func (s *FileJobStore) Len() int {
+	return s.mem.Len()
+}
+
+func (s *FileJobStore) path(id string) string {
+	sum := sha256.Sum256([]byte(id))
+
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:])+".json")
+}
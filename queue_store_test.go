@@ -0,0 +1,154 @@
+package interactor_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/interactor/v2"
+)
+
+func TestJobStores(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		newStore func(t *testing.T) interactor.JobStore
+	}{
+		{
+			name: "memory",
+			newStore: func(t *testing.T) interactor.JobStore {
+				return interactor.NewMemoryJobStore()
+			},
+		},
+		{
+			name: "file",
+			newStore: func(t *testing.T) interactor.JobStore {
+				store, err := interactor.NewFileJobStore(t.TempDir())
+				require.NoError(t, err)
+
+				return store
+			},
+		},
+	}
+
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			t.Run("due jobs are claimed by priority and run time", func(t *testing.T) {
+				t.Parallel()
+
+				// arrange
+				ctx := context.Background()
+				store := tc.newStore(t)
+
+				jobs := []interactor.Job{
+					{ID: "low", RunAt: now.Add(-time.Minute)},
+					{ID: "later", Priority: 1, RunAt: now},
+					{ID: "earlier", Priority: 1, RunAt: now.Add(-time.Second)},
+					{ID: "not due", Priority: 2, RunAt: now.Add(time.Second)},
+				}
+				for _, job := range jobs {
+					require.NoError(t, store.Save(ctx, job))
+				}
+
+				// act
+				var claimed []string
+
+				for {
+					job, ok, err := store.Claim(ctx, now)
+					require.NoError(t, err)
+
+					if !ok {
+						break
+					}
+
+					claimed = append(claimed, job.ID)
+				}
+
+				// assert
+				assert.Equal(t, []string{"earlier", "later", "low"}, claimed)
+			})
+
+			t.Run("a completed job is removed", func(t *testing.T) {
+				t.Parallel()
+
+				// arrange
+				ctx := context.Background()
+				store := tc.newStore(t)
+				require.NoError(t, store.Save(ctx, interactor.Job{ID: "123", RunAt: now}))
+
+				_, _, err := store.Claim(ctx, now)
+				require.NoError(t, err)
+
+				// act
+				err = store.Complete(ctx, "123")
+
+				// assert
+				require.NoError(t, err)
+				require.NoError(t, store.Save(ctx, interactor.Job{ID: "456", RunAt: now}))
+
+				job, ok, err := store.Claim(ctx, now)
+				require.NoError(t, err)
+				require.True(t, ok)
+				assert.Equal(t, "456", job.ID)
+			})
+
+			t.Run("a saved claimed job can be claimed again", func(t *testing.T) {
+				t.Parallel()
+
+				// arrange
+				ctx := context.Background()
+				store := tc.newStore(t)
+				require.NoError(t, store.Save(ctx, interactor.Job{ID: "123", RunAt: now}))
+
+				job, _, err := store.Claim(ctx, now)
+				require.NoError(t, err)
+
+				// act
+				require.NoError(t, store.Save(ctx, job))
+
+				// assert
+				job, ok, err := store.Claim(ctx, now)
+				require.NoError(t, err)
+				require.True(t, ok)
+				assert.Equal(t, "123", job.ID)
+			})
+		})
+	}
+
+	t.Run("a file store loads the stored jobs", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		ctx := context.Background()
+		dir := t.TempDir()
+
+		store, err := interactor.NewFileJobStore(dir)
+		require.NoError(t, err)
+
+		job := interactor.Job{ID: "123", Type: "place_order", Payload: []byte(`{"order_id":123}`), RunAt: now, EnqueuedAt: now}
+		require.NoError(t, store.Save(ctx, job))
+
+		_, _, err = store.Claim(ctx, now)
+		require.NoError(t, err)
+
+		// act
+		reopened, err := interactor.NewFileJobStore(dir)
+		require.NoError(t, err)
+
+		// assert
+		got, ok, err := reopened.Claim(ctx, now)
+		require.NoError(t, err)
+		require.True(t, ok)
+		assert.Equal(t, job, got)
+	})
+}
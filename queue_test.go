@@ -0,0 +1,268 @@
+package interactor_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/interactor/v2"
+)
+
+const (
+	waitFor = time.Second
+	tick    = time.Millisecond
+)
+
+func TestQueue(t *testing.T) {
+	t.Parallel()
+
+	t.Run("an enqueued request is run through the dispatcher", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		orders := &OrderLog{}
+		store := interactor.NewMemoryJobStore()
+		queue := newTestQueue(t, orders.dispatcher(), interactor.WithQueueStore(store))
+		require.NoError(t, queue.Start())
+
+		// act
+		id, err := queue.Enqueue(context.Background(), PlaceOrder{OrderID: 123})
+
+		// assert
+		require.NoError(t, err)
+		assert.NotEmpty(t, id)
+		assert.Eventually(t, func() bool { return len(orders.IDs()) == 1 }, waitFor, tick)
+		assert.Equal(t, []int{123}, orders.IDs())
+		assert.Eventually(t, func() bool { return store.Len() == 0 }, waitFor, tick)
+	})
+
+	t.Run("a delayed request is run once it is due", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		orders := &OrderLog{}
+		clock := NewFakeClock()
+		queue := newTestQueue(t, orders.dispatcher(),
+			interactor.WithQueueClock(clock.Now), interactor.WithQueuePollInterval(tick))
+		require.NoError(t, queue.Start())
+
+		_, err := queue.Enqueue(context.Background(), PlaceOrder{OrderID: 123}, interactor.WithJobDelay(time.Hour))
+		require.NoError(t, err)
+
+		time.Sleep(10 * tick)
+		require.Empty(t, orders.IDs())
+
+		// act
+		clock.Advance(time.Hour)
+
+		// assert
+		assert.Eventually(t, func() bool { return len(orders.IDs()) == 1 }, waitFor, tick)
+	})
+
+	t.Run("requests with higher priorities are run first", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		orders := &OrderLog{}
+		queue := newTestQueue(t, orders.dispatcher())
+
+		for _, priority := range []int{1, 3, 2, 3} {
+			_, err := queue.Enqueue(context.Background(), PlaceOrder{OrderID: priority}, interactor.WithJobPriority(priority))
+			require.NoError(t, err)
+		}
+
+		// act
+		require.NoError(t, queue.Start())
+
+		// assert
+		assert.Eventually(t, func() bool { return len(orders.IDs()) == 4 }, waitFor, tick)
+		assert.Equal(t, []int{3, 3, 2, 1}, orders.IDs())
+	})
+
+	t.Run("a request of an unregistered type cannot be enqueued", func(t *testing.T) {
+		t.Parallel()
+
+		queue := newTestQueue(t, interactor.NewDispatcher())
+
+		_, err := queue.Enqueue(context.Background(), DeleteUser{})
+		require.ErrorIs(t, err, interactor.ErrTypeNotRegistered)
+	})
+
+	t.Run("a failed job is reported", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		dispatcher := interactor.NewDispatcher()
+		dispatcher.Register(PlaceOrder{}, failingRunner(errSomeErr))
+
+		failures := make(chan error, 1)
+		store := interactor.NewMemoryJobStore()
+		queue := newTestQueue(t, dispatcher, interactor.WithQueueStore(store),
+			interactor.WithQueueErrorHandler(func(_ interactor.Job, err error) { failures <- err }))
+		require.NoError(t, queue.Start())
+
+		// act
+		_, err := queue.Enqueue(context.Background(), PlaceOrder{OrderID: 123})
+		require.NoError(t, err)
+
+		// assert
+		require.ErrorIs(t, <-failures, errSomeErr)
+		assert.Eventually(t, func() bool { return store.Len() == 0 }, waitFor, tick)
+	})
+
+	t.Run("shutdown waits for the running jobs", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		useCase := NewBlockingUseCase()
+		dispatcher := interactor.NewDispatcher()
+		dispatcher.Register(PlaceOrder{}, blocking(useCase))
+
+		store := interactor.NewMemoryJobStore()
+		queue := newTestQueue(t, dispatcher, interactor.WithQueueStore(store))
+		require.NoError(t, queue.Start())
+
+		_, err := queue.Enqueue(context.Background(), PlaceOrder{})
+		require.NoError(t, err)
+		useCase.Started(1)
+
+		time.AfterFunc(10*tick, useCase.Release)
+
+		// act
+		err = queue.Shutdown(context.Background())
+
+		// assert
+		require.NoError(t, err)
+		assert.Zero(t, store.Len())
+
+		_, err = queue.Enqueue(context.Background(), PlaceOrder{})
+		require.ErrorIs(t, err, interactor.ErrQueueClosed)
+		require.ErrorIs(t, queue.Start(), interactor.ErrQueueClosed)
+	})
+
+	t.Run("a job interrupted by the shutdown is kept", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		useCase := NewBlockingUseCase()
+		dispatcher := interactor.NewDispatcher()
+		dispatcher.Register(PlaceOrder{}, blocking(useCase))
+
+		store := interactor.NewMemoryJobStore()
+		queue := newTestQueue(t, dispatcher, interactor.WithQueueStore(store))
+		require.NoError(t, queue.Start())
+
+		_, err := queue.Enqueue(context.Background(), PlaceOrder{})
+		require.NoError(t, err)
+		useCase.Started(1)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*tick)
+		defer cancel()
+
+		// act
+		err = queue.Shutdown(ctx)
+
+		// assert
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		var job interactor.Job
+		assert.Eventually(t, func() bool {
+			var ok bool
+			job, ok, _ = store.Claim(context.Background(), time.Now())
+
+			return ok
+		}, waitFor, tick)
+		assert.Equal(t, "place_order", job.Type)
+	})
+
+	t.Run("jobs survive restarts", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		dir := t.TempDir()
+		orders := &OrderLog{}
+
+		store, err := interactor.NewFileJobStore(dir)
+		require.NoError(t, err)
+
+		stopped := newTestQueue(t, orders.dispatcher(), interactor.WithQueueStore(store))
+		_, err = stopped.Enqueue(context.Background(), PlaceOrder{OrderID: 123})
+		require.NoError(t, err)
+		require.NoError(t, stopped.Shutdown(context.Background()))
+
+		// act
+		reopened, err := interactor.NewFileJobStore(dir)
+		require.NoError(t, err)
+
+		queue := newTestQueue(t, orders.dispatcher(), interactor.WithQueueStore(reopened))
+		require.NoError(t, queue.Start())
+
+		// assert
+		assert.Eventually(t, func() bool { return len(orders.IDs()) == 1 }, waitFor, tick)
+		assert.Eventually(t, func() bool { return reopened.Len() == 0 }, waitFor, tick)
+	})
+}
+
+// newTestQueue creates a queue with the test request types registered, which is shut down at the end of the test.
+func newTestQueue(t *testing.T, dispatcher *interactor.Dispatcher, opts ...interactor.QueueOption) *interactor.Queue {
+	t.Helper()
+
+	queue := interactor.NewQueue(dispatcher, newTestRegistry(t), opts...)
+	t.Cleanup(func() { _ = queue.Shutdown(context.Background()) })
+
+	return queue
+}
+
+// newTestRegistry registers the test request types.
+func newTestRegistry(t *testing.T) *interactor.TypeRegistry {
+	t.Helper()
+
+	registry := interactor.NewTypeRegistry()
+	require.NoError(t, registry.Register("place_order", PlaceOrder{}, &PlaceOrderResponse{}))
+
+	return registry
+}
+
+// blocking runs the use case whatever the request is.
+func blocking(useCase *BlockingUseCase) interactor.UseCaseRunnerFn {
+	return func(ctx context.Context, _ interactor.Request, _ interactor.Response) error {
+		return useCase.Run(ctx, TestRequest{}, &TestResponse{})
+	}
+}
+
+// OrderLog records the IDs of the placed orders.
+type OrderLog struct {
+	mu  sync.Mutex
+	ids []int
+}
+
+func (l *OrderLog) dispatcher() *interactor.Dispatcher {
+	dispatcher := interactor.NewDispatcher()
+	dispatcher.Register(PlaceOrder{}, l.runner())
+
+	return dispatcher
+}
+
+func (l *OrderLog) runner() interactor.UseCaseRunnerFn {
+	return interactor.Must(interactor.Func(
+		func(_ context.Context, req PlaceOrder, _ *PlaceOrderResponse) error {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+
+			l.ids = append(l.ids, req.OrderID)
+
+			return nil
+		},
+	))
+}
+
+func (l *OrderLog) IDs() []int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return append([]int(nil), l.ids...)
+}
@@ -0,0 +1,184 @@
+package interactor
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+const defaultRateLimiterMaxKeys = 10000
+
+// RateLimit describes a token bucket.
+//
+// It can be loaded from configuration, e.g. from JSON:
+//
+//	{"rate": 10, "burst": 20}
+type RateLimit struct {
+	// Rate is the number of runs per second the bucket is refilled with.
+	Rate float64 `json:"rate"`
+	// Burst is the maximum number of runs allowed at once.
+	Burst int `json:"burst"`
+}
+
+// RateLimitedError is returned when a run exceeds the rate limit.
+//
+// It wraps ErrRateLimited, so it can be checked with errors.Is.
+type RateLimitedError struct {
+	// Key is the key the limit was exceeded for.
+	Key string
+	// RetryAfter is the time to wait before the next run is allowed.
+	//
+	// It is zero if the bucket is never refilled.
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitedError) Error() string {
+	return fmt.Sprintf("%s: %s, retry after %s", ErrRateLimited, e.Key, e.RetryAfter)
+}
+
+func (e *RateLimitedError) Unwrap() error {
+	return ErrRateLimited
+}
+
+// RateLimiterOption configures the RateLimiter middleware.
+type RateLimiterOption func(*rateLimiterConfig)
+
+type rateLimiterConfig struct {
+	key     KeyFunc
+	limits  map[string]RateLimit
+	maxKeys int
+	now     func() time.Time
+}
+
+// WithRateLimitKey sets the key each bucket is kept for.
+//
+// By default, each request type has its own bucket. Use ContextValueKey to limit runs per tenant or per user.
+func WithRateLimitKey(key KeyFunc) RateLimiterOption {
+	return func(cfg *rateLimiterConfig) {
+		cfg.key = key
+	}
+}
+
+// WithRateLimits overrides the default limit for the given keys.
+func WithRateLimits(limits map[string]RateLimit) RateLimiterOption {
+	return func(cfg *rateLimiterConfig) {
+		cfg.limits = limits
+	}
+}
+
+// WithRateLimiterMaxKeys bounds the number of buckets kept in memory.
+//
+// When the bound is reached, the least recently used bucket is dropped, so its key starts over with a full bucket.
+func WithRateLimiterMaxKeys(n int) RateLimiterOption {
+	return func(cfg *rateLimiterConfig) {
+		cfg.maxKeys = n
+	}
+}
+
+// WithRateLimiterClock sets the function used to get the current time.
+func WithRateLimiterClock(now func() time.Time) RateLimiterOption {
+	return func(cfg *rateLimiterConfig) {
+		cfg.now = now
+	}
+}
+
+// RateLimiter limits the rate of runs per key using a token bucket.
+//
+// When the limit is exceeded, it returns a *RateLimitedError without running the use case.
+func RateLimiter(limit RateLimit, opts ...RateLimiterOption) Middleware {
+	cfg := &rateLimiterConfig{
+		key:     RequestTypeKey,
+		maxKeys: defaultRateLimiterMaxKeys,
+		now:     time.Now,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	l := &rateLimiter{
+		cfg:     cfg,
+		limit:   limit,
+		buckets: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+
+	return func(next UseCaseRunnerFn) UseCaseRunnerFn {
+		return func(ctx context.Context, req Request, resp Response) error {
+			key := cfg.key(ctx, req)
+
+			if retryAfter, ok := l.allow(key); !ok {
+				return &RateLimitedError{Key: key, RetryAfter: retryAfter}
+			}
+
+			return next(ctx, req, resp)
+		}
+	}
+}
+
+type tokenBucket struct {
+	key    string
+	limit  RateLimit
+	tokens float64
+	last   time.Time
+}
+
+func (b *tokenBucket) take(now time.Time) (time.Duration, bool) {
+	elapsed := now.Sub(b.last).Seconds()
+	b.tokens = math.Min(float64(b.limit.Burst), b.tokens+elapsed*b.limit.Rate)
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+
+		return 0, true
+	}
+
+	if b.limit.Rate <= 0 {
+		return 0, false
+	}
+
+	return time.Duration((1 - b.tokens) / b.limit.Rate * float64(time.Second)), false
+}
+
+type rateLimiter struct {
+	cfg   *rateLimiterConfig
+	limit RateLimit
+
+	mu      sync.Mutex
+	buckets map[string]*list.Element
+	lru     *list.List
+}
+
+func (l *rateLimiter) allow(key string) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.bucket(key).take(l.cfg.now())
+}
+
+func (l *rateLimiter) bucket(key string) *tokenBucket {
+	if el, ok := l.buckets[key]; ok {
+		l.lru.MoveToFront(el)
+
+		return el.Value.(*tokenBucket)
+	}
+
+	limit, ok := l.cfg.limits[key]
+	if !ok {
+		limit = l.limit
+	}
+
+	b := &tokenBucket{key: key, limit: limit, tokens: float64(limit.Burst), last: l.cfg.now()}
+	l.buckets[key] = l.lru.PushFront(b)
+
+	for l.lru.Len() > l.cfg.maxKeys {
+		oldest := l.lru.Back()
+		l.lru.Remove(oldest)
+		delete(l.buckets, oldest.Value.(*tokenBucket).key)
+	}
+
+	return b
+}
@@ -0,0 +1,153 @@
+package interactor_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/interactor/v2"
+)
+
+type tenantKey struct{}
+
+func TestRateLimiter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("runs within the burst are allowed", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		runner := rateLimitedRunner(interactor.RateLimit{Rate: 1, Burst: 2})
+
+		// act
+		err1 := runner(context.Background(), TestRequest{}, &TestResponse{})
+		err2 := runner(context.Background(), TestRequest{}, &TestResponse{})
+
+		// assert
+		require.NoError(t, err1)
+		require.NoError(t, err2)
+	})
+
+	t.Run("when the limit is exceeded, an error with retry after is returned", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		clock := NewFakeClock()
+		runner := rateLimitedRunner(interactor.RateLimit{Rate: 2, Burst: 1}, interactor.WithRateLimiterClock(clock.Now))
+		require.NoError(t, runner(context.Background(), TestRequest{}, &TestResponse{}))
+
+		// act
+		err := runner(context.Background(), TestRequest{}, &TestResponse{})
+
+		// assert
+		require.ErrorIs(t, err, interactor.ErrRateLimited)
+
+		var rateLimitedErr *interactor.RateLimitedError
+		require.True(t, errors.As(err, &rateLimitedErr))
+		assert.Equal(t, "interactor_test.TestRequest", rateLimitedErr.Key)
+		assert.Equal(t, 500*time.Millisecond, rateLimitedErr.RetryAfter)
+	})
+
+	t.Run("the bucket is refilled over time", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		clock := NewFakeClock()
+		runner := rateLimitedRunner(interactor.RateLimit{Rate: 1, Burst: 1}, interactor.WithRateLimiterClock(clock.Now))
+		require.NoError(t, runner(context.Background(), TestRequest{}, &TestResponse{}))
+
+		// act
+		clock.Advance(time.Second)
+		err := runner(context.Background(), TestRequest{}, &TestResponse{})
+
+		// assert
+		require.NoError(t, err)
+	})
+
+	t.Run("each key has its own bucket", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		runner := rateLimitedRunner(
+			interactor.RateLimit{Rate: 1, Burst: 1},
+			interactor.WithRateLimitKey(interactor.ContextValueKey(tenantKey{})),
+		)
+
+		acme := context.WithValue(context.Background(), tenantKey{}, "acme")
+		globex := context.WithValue(context.Background(), tenantKey{}, "globex")
+		require.NoError(t, runner(acme, TestRequest{}, &TestResponse{}))
+
+		// act
+		acmeErr := runner(acme, TestRequest{}, &TestResponse{})
+		globexErr := runner(globex, TestRequest{}, &TestResponse{})
+
+		// assert
+		require.ErrorIs(t, acmeErr, interactor.ErrRateLimited)
+		require.NoError(t, globexErr)
+	})
+
+	t.Run("per key limits override the default one", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		runner := rateLimitedRunner(
+			interactor.RateLimit{Rate: 1, Burst: 1},
+			interactor.WithRateLimits(map[string]interactor.RateLimit{
+				"interactor_test.TestRequest": {Rate: 1, Burst: 2},
+			}),
+		)
+		require.NoError(t, runner(context.Background(), TestRequest{}, &TestResponse{}))
+
+		// act
+		err := runner(context.Background(), TestRequest{}, &TestResponse{})
+
+		// assert
+		require.NoError(t, err)
+	})
+
+	t.Run("when a bucket is never refilled, retry after is zero", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		runner := rateLimitedRunner(interactor.RateLimit{Rate: 0, Burst: 1})
+		require.NoError(t, runner(context.Background(), TestRequest{}, &TestResponse{}))
+
+		// act
+		err := runner(context.Background(), TestRequest{}, &TestResponse{})
+
+		// assert
+		var rateLimitedErr *interactor.RateLimitedError
+		require.True(t, errors.As(err, &rateLimitedErr))
+		assert.Zero(t, rateLimitedErr.RetryAfter)
+	})
+
+	t.Run("the least recently used bucket is dropped when there are too many keys", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		runner := rateLimitedRunner(
+			interactor.RateLimit{Rate: 0, Burst: 1},
+			interactor.WithRateLimitKey(interactor.ContextValueKey(tenantKey{})),
+			interactor.WithRateLimiterMaxKeys(1),
+		)
+
+		acme := context.WithValue(context.Background(), tenantKey{}, "acme")
+		globex := context.WithValue(context.Background(), tenantKey{}, "globex")
+		require.NoError(t, runner(acme, TestRequest{}, &TestResponse{}))
+		require.NoError(t, runner(globex, TestRequest{}, &TestResponse{}))
+
+		// act
+		err := runner(acme, TestRequest{}, &TestResponse{})
+
+		// assert
+		require.NoError(t, err)
+	})
+}
+
+func rateLimitedRunner(limit interactor.RateLimit, opts ...interactor.RateLimiterOption) interactor.UseCaseRunnerFn {
+	return interactor.Chain(interactor.MustAdapt(ConcreteUseCase{}), interactor.RateLimiter(limit, opts...))
+}
@@ -0,0 +1,109 @@
+package interactor
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// TypeRegistry maps request types to stable names, so requests can be serialized and run later,
+// e.g. by a Queue or an outbox relay.
+//
+// Requests are encoded as JSON, so only their exported fields survive serialization.
+// Request types which JSON cannot encode without losing data are rejected when registered.
+type TypeRegistry struct {
+	mu     sync.RWMutex
+	byName map[string]registeredType
+	byType map[reflect.Type]string
+}
+
+type registeredType struct {
+	reqType reflect.Type
+	resp    Response
+}
+
+// NewTypeRegistry creates a new empty TypeRegistry.
+func NewTypeRegistry() *TypeRegistry {
+	return &TypeRegistry{
+		byName: make(map[string]registeredType),
+		byType: make(map[reflect.Type]string),
+	}
+}
+
+// Register registers the type of the given request under the provided name.
+//
+// The response is a prototype of the response the decoded requests are run with. It must be a pointer.
+//
+// It returns ErrRequestNotStorable if the request has unexported fields, which JSON leaves out,
+// unless the type encodes itself.
+func (r *TypeRegistry) Register(name string, req Request, resp Response) error {
+	reqType := reflect.TypeOf(req)
+	if field := lossyField(reqType, make(map[reflect.Type]bool)); field != "" {
+		return fmt.Errorf("%w: %s has unexported field %s", ErrRequestNotStorable, reqType, field)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.byName[name] = registeredType{reqType: reqType, resp: resp}
+	r.byType[reqType] = name
+
+	return nil
+}
+
+// Encode returns the registered name of the request type along with the request encoded as JSON.
+//
+// It returns ErrTypeNotRegistered if the request type is not registered.
+func (r *TypeRegistry) Encode(req Request) (string, json.RawMessage, error) {
+	r.mu.RLock()
+	name, ok := r.byType[reflect.TypeOf(req)]
+	r.mu.RUnlock()
+
+	if !ok {
+		return "", nil, fmt.Errorf("%w: %T", ErrTypeNotRegistered, req)
+	}
+
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return "", nil, fmt.Errorf("cannot encode %T: %w", req, err)
+	}
+
+	return name, payload, nil
+}
+
+// Decode decodes the request registered under the given name and returns it along with a new response to run it with.
+//
+// It returns ErrTypeNotRegistered if no type is registered under the name.
+func (r *TypeRegistry) Decode(name string, payload json.RawMessage) (Request, Response, error) {
+	r.mu.RLock()
+	registered, ok := r.byName[name]
+	r.mu.RUnlock()
+
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %s", ErrTypeNotRegistered, name)
+	}
+
+	reqType := registered.reqType
+
+	isPtr := reqType.Kind() == reflect.Ptr
+	if isPtr {
+		reqType = reqType.Elem()
+	}
+
+	req := reflect.New(reqType)
+	if err := json.Unmarshal(payload, req.Interface()); err != nil {
+		return nil, nil, fmt.Errorf("cannot decode %s: %w", name, err)
+	}
+
+	if !isPtr {
+		req = req.Elem()
+	}
+
+	resp, err := newResponseLike(registered.resp)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot decode %s: %w", name, err)
+	}
+
+	return req.Interface(), resp, nil
+}
@@ -0,0 +1,82 @@
+package interactor_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/interactor/v2"
+)
+
+func TestTypeRegistry(t *testing.T) {
+	t.Parallel()
+
+	t.Run("a request survives encoding", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		registry := interactor.NewTypeRegistry()
+		require.NoError(t, registry.Register("place_order", PlaceOrder{}, &PlaceOrderResponse{}))
+
+		name, payload, err := registry.Encode(PlaceOrder{Key: "abc", OrderID: 123})
+		require.NoError(t, err)
+
+		// act
+		req, resp, err := registry.Decode(name, payload)
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, "place_order", name)
+		assert.Equal(t, PlaceOrder{Key: "abc", OrderID: 123}, req)
+		assert.Equal(t, &PlaceOrderResponse{}, resp)
+	})
+
+	t.Run("a pointer request survives encoding", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		registry := interactor.NewTypeRegistry()
+		require.NoError(t, registry.Register("place_order", &PlaceOrder{}, &PlaceOrderResponse{}))
+
+		name, payload, err := registry.Encode(&PlaceOrder{OrderID: 123})
+		require.NoError(t, err)
+
+		// act
+		req, _, err := registry.Decode(name, payload)
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, &PlaceOrder{OrderID: 123}, req)
+	})
+
+	t.Run("a request type losing data in JSON cannot be registered", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		registry := interactor.NewTypeRegistry()
+
+		// act
+		err := registry.Register("test", TestRequest{}, &TestResponse{})
+
+		// assert
+		require.ErrorIs(t, err, interactor.ErrRequestNotStorable)
+
+		_, _, err = registry.Encode(TestRequest{id: 7})
+		require.ErrorIs(t, err, interactor.ErrTypeNotRegistered)
+	})
+
+	t.Run("an unregistered type cannot be encoded", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, err := interactor.NewTypeRegistry().Encode(PlaceOrder{})
+		require.ErrorIs(t, err, interactor.ErrTypeNotRegistered)
+	})
+
+	t.Run("an unregistered name cannot be decoded", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, err := interactor.NewTypeRegistry().Decode("place_order", []byte("{}"))
+		require.ErrorIs(t, err, interactor.ErrTypeNotRegistered)
+	})
+}
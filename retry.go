@@ -0,0 +1,37 @@
+package interactor
+
+import "time"
+
+// RetryPolicy decides how many times a failing job is run and how long to wait between the runs.
+type RetryPolicy struct {
+	// MaxAttempts is the number of times a job is run before it is given up on. Zero or one means no retries.
+	MaxAttempts int
+	// Backoff returns the delay before the run following the given failed attempt, starting from 1.
+	// If nil, the job is retried right away.
+	Backoff func(attempt int) time.Duration
+}
+
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	if p.Backoff == nil {
+		return 0
+	}
+
+	return p.Backoff(attempt)
+}
+
+// ExponentialBackoff returns a backoff which doubles the delay after every attempt, starting from base,
+// up to the given maximum.
+func ExponentialBackoff(base, maximum time.Duration) func(attempt int) time.Duration {
+	return func(attempt int) time.Duration {
+		delay := base
+		for i := 1; i < attempt && delay < maximum; i++ {
+			delay *= 2
+		}
+
+		if delay > maximum {
+			return maximum
+		}
+
+		return delay
+	}
+}
@@ -0,0 +1,32 @@
+package interactor_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/screwyprof/interactor/v2"
+)
+
+func TestExponentialBackoff(t *testing.T) {
+	t.Parallel()
+
+	backoff := interactor.ExponentialBackoff(time.Second, 10*time.Second)
+
+	testCases := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: 1, want: time.Second},
+		{attempt: 2, want: 2 * time.Second},
+		{attempt: 3, want: 4 * time.Second},
+		{attempt: 4, want: 8 * time.Second},
+		{attempt: 5, want: 10 * time.Second},
+		{attempt: 100, want: 10 * time.Second},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.want, backoff(tc.attempt), "attempt %d", tc.attempt)
+	}
+}
@@ -0,0 +1,162 @@
+package interactor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type sagaStep struct {
+	name         string
+	action       UseCaseRunner
+	compensation UseCaseRunner
+}
+
+// Saga runs use cases in order and compensates the completed ones in reverse order if a later one fails.
+//
+// Every step gets the request and the response the saga is run with:
+//
+//	saga := interactor.NewSaga().
+//		Step("reserve stock", reserveStock, releaseStock).
+//		Step("charge card", chargeCard, refundCard).
+//		Step("send confirmation", sendConfirmation, nil)
+//
+//	dispatcher.Register(PlaceOrder{}, saga.Runner())
+type Saga struct {
+	steps               []sagaStep
+	compensationTimeout time.Duration
+}
+
+// SagaOption configures a Saga.
+type SagaOption func(*Saga)
+
+// WithCompensationTimeout limits how long each compensation may run, so a hung one does not block the saga forever.
+//
+// By default, compensations are not limited.
+func WithCompensationTimeout(timeout time.Duration) SagaOption {
+	return func(s *Saga) {
+		s.compensationTimeout = timeout
+	}
+}
+
+// NewSaga creates a new empty Saga.
+func NewSaga(opts ...SagaOption) *Saga {
+	s := &Saga{}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Step adds a step to the saga.
+//
+// The compensation undoes the action. It may be nil if the action needs no compensation.
+func (s *Saga) Step(name string, action, compensation UseCaseRunner) *Saga {
+	s.steps = append(s.steps, sagaStep{name: name, action: action, compensation: compensation})
+
+	return s
+}
+
+// SagaError reports the failed step of a saga along with the compensations which failed.
+type SagaError struct {
+	// Step is the name of the failed step.
+	Step string
+	// Err is the error the step failed with.
+	Err error
+	// CompensationErrors holds the errors of the failed compensations, each wrapped into *SagaCompensationError.
+	CompensationErrors []error
+}
+
+func (e *SagaError) Error() string {
+	msg := fmt.Sprintf("saga step %q failed: %v", e.Step, e.Err)
+	if len(e.CompensationErrors) == 0 {
+		return msg
+	}
+
+	compensations := make([]string, len(e.CompensationErrors))
+	for i, err := range e.CompensationErrors {
+		compensations[i] = err.Error()
+	}
+
+	return msg + "; " + strings.Join(compensations, "; ")
+}
+
+// Unwrap returns the step error followed by the compensation errors.
+func (e *SagaError) Unwrap() []error {
+	return append([]error{e.Err}, e.CompensationErrors...)
+}
+
+// SagaCompensationError reports a failed compensation.
+type SagaCompensationError struct {
+	// Step is the name of the step whose compensation failed.
+	Step string
+	// Err is the error the compensation failed with.
+	Err error
+}
+
+func (e *SagaCompensationError) Error() string {
+	return fmt.Sprintf("compensation of saga step %q failed: %v", e.Step, e.Err)
+}
+
+func (e *SagaCompensationError) Unwrap() error {
+	return e.Err
+}
+
+// Runner returns the saga as a single use case runner.
+//
+// If a step fails, the compensations of the completed steps are run in reverse order and *SagaError is returned.
+// A step which panics fails with ErrUseCasePanicked, so the completed steps are compensated as well.
+// Compensations are run even if the context is done, as they must not be skipped, but they are cancelled once
+// the compensation timeout elapses, see WithCompensationTimeout. All the compensations are run,
+// even if some of them fail.
+func (s *Saga) Runner() UseCaseRunnerFn {
+	steps := append([]sagaStep(nil), s.steps...)
+	timeout := s.compensationTimeout
+
+	return func(ctx context.Context, req Request, resp Response) error {
+		for i, step := range steps {
+			err := runRecovered(ctx, step.action.Run, req, resp)
+			if err == nil {
+				continue
+			}
+
+			return &SagaError{
+				Step:               step.name,
+				Err:                err,
+				CompensationErrors: compensate(detach(ctx), timeout, steps[:i], req, resp),
+			}
+		}
+
+		return nil
+	}
+}
+
+func compensate(ctx context.Context, timeout time.Duration, completed []sagaStep, req Request, resp Response) []error {
+	var errs []error
+
+	for i := len(completed) - 1; i >= 0; i-- {
+		step := completed[i]
+		if step.compensation == nil {
+			continue
+		}
+
+		if err := runCompensation(ctx, timeout, step, req, resp); err != nil {
+			errs = append(errs, &SagaCompensationError{Step: step.name, Err: err})
+		}
+	}
+
+	return errs
+}
+
+func runCompensation(ctx context.Context, timeout time.Duration, step sagaStep, req Request, resp Response) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	return runRecovered(ctx, step.compensation.Run, req, resp)
+}
@@ -0,0 +1,179 @@
+package interactor_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/interactor/v2"
+)
+
+var errCompensation = errors.New("compensation error")
+
+func TestSaga(t *testing.T) {
+	t.Parallel()
+
+	t.Run("all the steps are run in order", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		journal := &Journal{}
+		saga := interactor.NewSaga().
+			Step("reserve", journal.runner("reserve", nil), journal.runner("release", nil)).
+			Step("charge", journal.runner("charge", nil), journal.runner("refund", nil))
+
+		// act
+		err := saga.Runner()(context.Background(), TestRequest{}, &TestResponse{})
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, []string{"reserve", "charge"}, journal.entries())
+	})
+
+	t.Run("the completed steps are compensated in reverse order on failure", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		journal := &Journal{}
+		saga := interactor.NewSaga().
+			Step("reserve", journal.runner("reserve", nil), journal.runner("release", nil)).
+			Step("notify", journal.runner("notify", nil), nil).
+			Step("charge", journal.runner("charge", nil), journal.runner("refund", nil)).
+			Step("ship", journal.runner("ship", errSomeErr), journal.runner("cancel shipment", nil))
+
+		// act
+		err := saga.Runner()(context.Background(), TestRequest{}, &TestResponse{})
+
+		// assert
+		require.ErrorIs(t, err, errSomeErr)
+		assert.Equal(t, []string{"reserve", "notify", "charge", "ship", "refund", "release"}, journal.entries())
+
+		var sagaErr *interactor.SagaError
+		require.True(t, errors.As(err, &sagaErr))
+		assert.Equal(t, "ship", sagaErr.Step)
+		assert.Empty(t, sagaErr.CompensationErrors)
+	})
+
+	t.Run("the failed compensations are reported along with the original failure", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		journal := &Journal{}
+		saga := interactor.NewSaga().
+			Step("reserve", journal.runner("reserve", nil), journal.runner("release", nil)).
+			Step("charge", journal.runner("charge", nil), journal.runner("refund", errCompensation)).
+			Step("ship", journal.runner("ship", errSomeErr), nil)
+
+		// act
+		err := saga.Runner()(context.Background(), TestRequest{}, &TestResponse{})
+
+		// assert
+		require.ErrorIs(t, err, errSomeErr)
+		require.ErrorIs(t, err, errCompensation)
+		assert.Equal(t, []string{"reserve", "charge", "ship", "refund", "release"}, journal.entries())
+
+		var compensationErr *interactor.SagaCompensationError
+		require.True(t, errors.As(err, &compensationErr))
+		assert.Equal(t, "charge", compensationErr.Step)
+		assert.Equal(t,
+			`saga step "ship" failed: some error; compensation of saga step "charge" failed: compensation error`,
+			err.Error(),
+		)
+	})
+
+	t.Run("compensations are run even if the context is done", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		ctx, cancel := context.WithCancel(context.Background())
+
+		var compensationCtxErr error
+		saga := interactor.NewSaga().
+			Step("reserve", interactor.UseCaseRunnerFn(succeedingRunner), interactor.UseCaseRunnerFn(
+				func(ctx context.Context, _ interactor.Request, _ interactor.Response) error {
+					compensationCtxErr = ctx.Err()
+
+					return nil
+				},
+			)).
+			Step("charge", interactor.UseCaseRunnerFn(
+				func(context.Context, interactor.Request, interactor.Response) error {
+					cancel()
+
+					return context.Canceled
+				},
+			), nil)
+
+		// act
+		err := saga.Runner()(ctx, TestRequest{}, &TestResponse{})
+
+		// assert
+		require.ErrorIs(t, err, context.Canceled)
+		assert.NoError(t, compensationCtxErr)
+	})
+
+	t.Run("the completed steps are compensated when a step panics", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		journal := &Journal{}
+		saga := interactor.NewSaga().
+			Step("reserve", journal.runner("reserve", nil), journal.runner("release", nil)).
+			Step("charge", interactor.UseCaseRunnerFn(func(context.Context, interactor.Request, interactor.Response) error {
+				panic("boom")
+			}), journal.runner("refund", nil))
+
+		// act
+		err := saga.Runner()(context.Background(), TestRequest{}, &TestResponse{})
+
+		// assert
+		require.ErrorIs(t, err, interactor.ErrUseCasePanicked)
+		assert.Equal(t, []string{"reserve", "release"}, journal.entries())
+	})
+
+	t.Run("a compensation is cancelled once its timeout elapses", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		journal := &Journal{}
+		saga := interactor.NewSaga(interactor.WithCompensationTimeout(10*tick)).
+			Step("reserve", journal.runner("reserve", nil), journal.runner("release", nil)).
+			Step("charge", journal.runner("charge", nil), interactor.UseCaseRunnerFn(
+				func(ctx context.Context, _ interactor.Request, _ interactor.Response) error {
+					<-ctx.Done()
+
+					return ctx.Err()
+				},
+			)).
+			Step("ship", journal.runner("ship", errSomeErr), nil)
+
+		// act
+		err := saga.Runner()(context.Background(), TestRequest{}, &TestResponse{})
+
+		// assert
+		require.ErrorIs(t, err, errSomeErr)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, []string{"reserve", "charge", "ship", "release"}, journal.entries())
+	})
+
+	t.Run("a saga can be registered on a dispatcher", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		saga := interactor.NewSaga().Step("place", interactor.MustAdapt(&PlaceOrderUseCase{}), nil)
+
+		dispatcher := interactor.NewDispatcher()
+		dispatcher.Register(PlaceOrder{}, saga.Runner())
+
+		// act
+		var res PlaceOrderResponse
+		err := dispatcher.Run(context.Background(), PlaceOrder{OrderID: 123}, &res)
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, 123, res.OrderID)
+	})
+}
@@ -0,0 +1,375 @@
+package interactor
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// MissedRunPolicy decides what happens to the runs which could not start on time, because the previous run
+// was still in progress or the scheduler was delayed.
+type MissedRunPolicy int
+
+const (
+	// MissedRunSkip drops the missed runs. The next run happens at the next time in the future.
+	MissedRunSkip MissedRunPolicy = iota
+	// MissedRunOnce makes a single run as soon as possible to catch up with all the missed ones.
+	MissedRunOnce
+	// MissedRunAll makes every missed run, one after another.
+	MissedRunAll
+)
+
+// ScheduleOption configures a scheduled request.
+type ScheduleOption func(*scheduledEntry)
+
+// WithJitter delays every run by a random duration up to the given maximum,
+// so that several processes running the same schedule do not run it at once.
+func WithJitter(maximum time.Duration) ScheduleOption {
+	return func(e *scheduledEntry) {
+		e.jitter = maximum
+	}
+}
+
+// WithOverlap allows a run to start while the previous one is still in progress.
+//
+// By default, runs do not overlap: the runs due while the previous one is in progress are missed,
+// see WithMissedRunPolicy.
+func WithOverlap() ScheduleOption {
+	return func(e *scheduledEntry) {
+		e.overlap = true
+	}
+}
+
+// WithMissedRunPolicy sets what happens to the missed runs. It defaults to MissedRunSkip.
+func WithMissedRunPolicy(policy MissedRunPolicy) ScheduleOption {
+	return func(e *scheduledEntry) {
+		e.missedRunPolicy = policy
+	}
+}
+
+// SchedulerOption configures a Scheduler.
+type SchedulerOption func(*Scheduler)
+
+// WithSchedulerClock sets the clock the scheduler uses. It defaults to SystemClock.
+func WithSchedulerClock(clock Clock) SchedulerOption {
+	return func(s *Scheduler) {
+		s.clock = clock
+	}
+}
+
+// WithSchedulerErrorHandler sets the function the failed runs are reported to along with the names of their entries.
+//
+// A run whose use case panics is reported as ErrUseCasePanicked.
+func WithSchedulerErrorHandler(handler func(name string, err error)) SchedulerOption {
+	return func(s *Scheduler) {
+		s.onError = handler
+	}
+}
+
+// ScheduleEntry describes a scheduled request.
+type ScheduleEntry struct {
+	Name string
+	// Next is the time the next run is due at, not including the jitter. It is zero if there are no more runs.
+	Next time.Time
+	// LastRun is the time the last run started at. It is zero if the request has not run yet.
+	LastRun time.Time
+	// LastErr is the error the last run failed with, if any.
+	LastErr error
+	// Running is the number of the runs in progress.
+	Running int
+}
+
+type scheduledEntry struct {
+	name            string
+	schedule        Schedule
+	req             Request
+	resp            Response
+	jitter          time.Duration
+	overlap         bool
+	missedRunPolicy MissedRunPolicy
+
+	// The state is guarded by the scheduler's mutex.
+	next    time.Time
+	lastRun time.Time
+	lastErr error
+	running int
+}
+
+// Scheduler runs requests through a Dispatcher on cron expressions or at fixed intervals:
+//
+//	scheduler := interactor.NewScheduler(dispatcher)
+//	err := scheduler.Add("nightly cleanup", interactor.MustParseCron("0 3 * * *"), Cleanup{}, &CleanupResponse{})
+type Scheduler struct {
+	dispatcher *Dispatcher
+	clock      Clock
+	onError    func(name string, err error)
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+
+	// interrupt is closed when the shutdown times out to cancel the contexts of the runs in progress.
+	interrupt chan struct{}
+
+	mu          sync.Mutex
+	entries     []*scheduledEntry
+	started     bool
+	closed      bool
+	interrupted bool
+}
+
+// NewScheduler creates a new Scheduler instance which runs requests through the given dispatcher.
+//
+// The requests are not run until Start is called.
+func NewScheduler(dispatcher *Dispatcher, opts ...SchedulerOption) *Scheduler {
+	s := &Scheduler{
+		dispatcher: dispatcher,
+		clock:      SystemClock,
+		onError:    func(string, error) {},
+		stop:       make(chan struct{}),
+		interrupt:  make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Add schedules the request to be run on the given schedule.
+//
+// Every run gets a new response of the same type as the given one, which must be a pointer.
+// It returns ErrDuplicateSchedule if an entry with the same name is already added
+// and ErrSchedulerClosed if the scheduler is shut down.
+func (s *Scheduler) Add(name string, schedule Schedule, req Request, resp Response, opts ...ScheduleOption) error {
+	if _, err := newResponseLike(resp); err != nil {
+		return fmt.Errorf("cannot schedule %s: %w", name, err)
+	}
+
+	e := &scheduledEntry{name: name, schedule: schedule, req: req, resp: resp}
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrSchedulerClosed
+	}
+
+	for _, existing := range s.entries {
+		if existing.name == name {
+			return fmt.Errorf("%w: %s", ErrDuplicateSchedule, name)
+		}
+	}
+
+	s.entries = append(s.entries, e)
+
+	if s.started {
+		s.launch(e)
+	}
+
+	return nil
+}
+
+// Entries describes the scheduled requests in the order they were added.
+func (s *Scheduler) Entries() []ScheduleEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries := make([]ScheduleEntry, len(s.entries))
+	for i, e := range s.entries {
+		entries[i] = ScheduleEntry{Name: e.name, Next: e.next, LastRun: e.lastRun, LastErr: e.lastErr, Running: e.running}
+	}
+
+	return entries
+}
+
+// Start starts running the scheduled requests. The first run of each request is the first one due after the start.
+//
+// It does nothing if the scheduler is already started and returns ErrSchedulerClosed if it is shut down.
+func (s *Scheduler) Start() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrSchedulerClosed
+	}
+
+	if s.started {
+		return nil
+	}
+
+	s.started = true
+
+	for _, e := range s.entries {
+		s.launch(e)
+	}
+
+	return nil
+}
+
+// Shutdown stops scheduling new runs and waits for the ones in progress to complete.
+//
+// If the context is done first, the contexts of the runs in progress are cancelled and the context error is returned
+// without waiting for them.
+func (s *Scheduler) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.stop)
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		if !s.interrupted {
+			s.interrupted = true
+			close(s.interrupt)
+		}
+		s.mu.Unlock()
+
+		return ctx.Err()
+	}
+}
+
+// launch starts the loop of the entry. It must be called with the mutex held.
+func (s *Scheduler) launch(e *scheduledEntry) {
+	e.next = e.schedule.Next(s.clock.Now())
+
+	s.wg.Add(1)
+
+	go s.loop(e, e.next)
+}
+
+func (s *Scheduler) loop(e *scheduledEntry, next time.Time) {
+	defer s.wg.Done()
+
+	for !next.IsZero() {
+		if !s.waitUntil(next.Add(randomJitter(e.jitter))) {
+			return
+		}
+
+		s.trigger(e)
+
+		next = s.following(e, next)
+
+		s.mu.Lock()
+		e.next = next
+		s.mu.Unlock()
+	}
+}
+
+// waitUntil waits until the given time. It returns false if the scheduler is shut down first.
+func (s *Scheduler) waitUntil(at time.Time) bool {
+	d := at.Sub(s.clock.Now())
+	if d <= 0 {
+		select {
+		case <-s.stop:
+			return false
+		default:
+			return true
+		}
+	}
+
+	timer := s.clock.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-s.stop:
+		return false
+	case <-timer.C():
+		return true
+	}
+}
+
+func (s *Scheduler) trigger(e *scheduledEntry) {
+	if !e.overlap {
+		s.run(e)
+
+		return
+	}
+
+	s.wg.Add(1)
+
+	go func() {
+		defer s.wg.Done()
+
+		s.run(e)
+	}()
+}
+
+func (s *Scheduler) run(e *scheduledEntry) {
+	// The response is validated when the entry is added.
+	resp, _ := newResponseLike(e.resp)
+
+	s.mu.Lock()
+	e.lastRun = s.clock.Now()
+	e.running++
+	s.mu.Unlock()
+
+	ctx, cancel := contextUntil(s.interrupt)
+	err := runRecovered(ctx, s.dispatcher.Run, e.req, resp)
+	cancel()
+
+	s.mu.Lock()
+	e.lastErr = err
+	e.running--
+	s.mu.Unlock()
+
+	if err != nil {
+		s.onError(e.name, err)
+	}
+}
+
+// following returns the time of the run following the given one according to the missed run policy.
+func (s *Scheduler) following(e *scheduledEntry, prev time.Time) time.Time {
+	now := s.clock.Now()
+
+	next := e.schedule.Next(prev)
+	if next.IsZero() || next.After(now) {
+		return next
+	}
+
+	switch e.missedRunPolicy {
+	case MissedRunAll:
+		return next
+	case MissedRunOnce:
+		// Catch up with the latest missed run.
+		for {
+			following := e.schedule.Next(next)
+			if following.IsZero() || following.After(now) {
+				return next
+			}
+
+			next = following
+		}
+	case MissedRunSkip:
+	}
+
+	for !next.IsZero() && !next.After(now) {
+		next = e.schedule.Next(next)
+	}
+
+	return next
+}
+
+func randomJitter(maximum time.Duration) time.Duration {
+	if maximum <= 0 {
+		return 0
+	}
+
+	return time.Duration(rand.Int63n(int64(maximum))) //nolint:gosec // Jitter needs no cryptographic randomness.
+}
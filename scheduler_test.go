@@ -0,0 +1,355 @@
+package interactor_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/interactor/v2"
+)
+
+func TestScheduler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("a request is run on its schedule", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		clock := NewFakeClock()
+		start := clock.Now()
+
+		useCase := &ScheduledUseCase{}
+		scheduler := newTestScheduler(t, useCase, clock)
+		require.NoError(t, scheduler.Add("cleanup", interactor.Every(time.Minute), TestRequest{}, &TestResponse{}))
+		require.NoError(t, scheduler.Start())
+
+		require.Equal(t, start.Add(time.Minute), scheduler.Entries()[0].Next)
+		waitForTimers(t, clock, 1)
+
+		// act
+		clock.Advance(time.Minute)
+
+		// assert
+		assert.Eventually(t, func() bool { return useCase.Runs() == 1 }, waitFor, tick)
+		assert.Eventually(t, func() bool {
+			return scheduler.Entries()[0].Next.Equal(start.Add(2 * time.Minute))
+		}, waitFor, tick)
+
+		entry := scheduler.Entries()[0]
+		assert.Equal(t, "cleanup", entry.Name)
+		assert.Equal(t, start.Add(time.Minute), entry.LastRun)
+		assert.NoError(t, entry.LastErr)
+	})
+
+	t.Run("a request is run on a cron expression", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		clock := NewFakeClock()
+		start := clock.Now()
+
+		useCase := &ScheduledUseCase{}
+		scheduler := newTestScheduler(t, useCase, clock)
+		require.NoError(t, scheduler.Add("report", interactor.MustParseCron("30 * * * *"), TestRequest{}, &TestResponse{}))
+		require.NoError(t, scheduler.Start())
+		waitForTimers(t, clock, 1)
+
+		// act
+		clock.Advance(29 * time.Minute)
+		time.Sleep(10 * tick)
+		require.Zero(t, useCase.Runs())
+
+		clock.Advance(time.Minute)
+
+		// assert
+		assert.Eventually(t, func() bool { return useCase.Runs() == 1 }, waitFor, tick)
+		assert.Eventually(t, func() bool {
+			return scheduler.Entries()[0].Next.Equal(start.Add(90 * time.Minute))
+		}, waitFor, tick)
+	})
+
+	t.Run("a run is delayed by the jitter", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		clock := NewFakeClock()
+
+		useCase := &ScheduledUseCase{}
+		scheduler := newTestScheduler(t, useCase, clock)
+		require.NoError(t, scheduler.Add("cleanup", interactor.Every(time.Hour), TestRequest{}, &TestResponse{},
+			interactor.WithJitter(time.Minute)))
+		require.NoError(t, scheduler.Start())
+		waitForTimers(t, clock, 1)
+
+		// act
+		clock.Advance(time.Hour + time.Minute)
+
+		// assert
+		assert.Eventually(t, func() bool { return useCase.Runs() == 1 }, waitFor, tick)
+	})
+
+	t.Run("missed runs", func(t *testing.T) {
+		t.Parallel()
+
+		testCases := []struct {
+			name     string
+			policy   interactor.MissedRunPolicy
+			wantRuns int
+		}{
+			{name: "are skipped by default", policy: interactor.MissedRunSkip, wantRuns: 1},
+			{name: "are caught up with once", policy: interactor.MissedRunOnce, wantRuns: 2},
+			{name: "are all made", policy: interactor.MissedRunAll, wantRuns: 4},
+		}
+
+		for _, tc := range testCases {
+			tc := tc
+
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+
+				// arrange
+				clock := NewFakeClock()
+				start := clock.Now()
+
+				useCase := &ScheduledUseCase{blocked: make(chan struct{})}
+				scheduler := newTestScheduler(t, useCase, clock)
+				require.NoError(t, scheduler.Add("cleanup", interactor.Every(time.Minute), TestRequest{}, &TestResponse{},
+					interactor.WithMissedRunPolicy(tc.policy)))
+				require.NoError(t, scheduler.Start())
+				waitForTimers(t, clock, 1)
+
+				clock.Advance(time.Minute)
+				require.Eventually(t, func() bool { return useCase.Runs() == 1 }, waitFor, tick)
+
+				// act
+				clock.Advance(3 * time.Minute)
+				close(useCase.blocked)
+
+				// assert
+				assert.Eventually(t, func() bool {
+					return scheduler.Entries()[0].Next.Equal(start.Add(5 * time.Minute))
+				}, waitFor, tick)
+				assert.Equal(t, tc.wantRuns, useCase.Runs())
+			})
+		}
+	})
+
+	t.Run("runs overlap if allowed", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		clock := NewFakeClock()
+
+		useCase := &ScheduledUseCase{blocked: make(chan struct{})}
+		scheduler := newTestScheduler(t, useCase, clock)
+		require.NoError(t, scheduler.Add("cleanup", interactor.Every(time.Minute), TestRequest{}, &TestResponse{},
+			interactor.WithOverlap()))
+		require.NoError(t, scheduler.Start())
+
+		defer close(useCase.blocked)
+
+		// act
+		for i := 0; i < 2; i++ {
+			waitForTimers(t, clock, 1)
+			clock.Advance(time.Minute)
+		}
+
+		// assert
+		assert.Eventually(t, func() bool { return scheduler.Entries()[0].Running == 2 }, waitFor, tick)
+	})
+
+	t.Run("a failed run is reported", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		clock := NewFakeClock()
+
+		dispatcher := interactor.NewDispatcher()
+		dispatcher.Register(TestRequest{}, failingRunner(errSomeErr))
+
+		failures := make(chan string, 1)
+		scheduler := interactor.NewScheduler(dispatcher, interactor.WithSchedulerClock(clock),
+			interactor.WithSchedulerErrorHandler(func(name string, err error) {
+				if assert.ErrorIs(t, err, errSomeErr) {
+					failures <- name
+				}
+			}))
+		t.Cleanup(func() { _ = scheduler.Shutdown(context.Background()) })
+
+		require.NoError(t, scheduler.Add("cleanup", interactor.Every(time.Minute), TestRequest{}, &TestResponse{}))
+		require.NoError(t, scheduler.Start())
+		waitForTimers(t, clock, 1)
+
+		// act
+		clock.Advance(time.Minute)
+
+		// assert
+		assert.Equal(t, "cleanup", <-failures)
+		assert.Eventually(t, func() bool { return scheduler.Entries()[0].LastErr != nil }, waitFor, tick)
+	})
+
+	t.Run("a panicking run is reported and the next runs carry on", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		clock := NewFakeClock()
+
+		dispatcher := interactor.NewDispatcher()
+		dispatcher.Register(TestRequest{}, func(context.Context, interactor.Request, interactor.Response) error {
+			panic("boom")
+		})
+
+		failures := make(chan error, 2)
+		scheduler := interactor.NewScheduler(dispatcher, interactor.WithSchedulerClock(clock),
+			interactor.WithSchedulerErrorHandler(func(_ string, err error) { failures <- err }))
+		t.Cleanup(func() { _ = scheduler.Shutdown(context.Background()) })
+
+		require.NoError(t, scheduler.Add("cleanup", interactor.Every(time.Minute), TestRequest{}, &TestResponse{}))
+		require.NoError(t, scheduler.Start())
+
+		// act
+		for i := 0; i < cap(failures); i++ {
+			waitForTimers(t, clock, 1)
+			clock.Advance(time.Minute)
+
+			// assert
+			require.ErrorIs(t, <-failures, interactor.ErrUseCasePanicked)
+		}
+	})
+
+	t.Run("an entry name must be unique", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		scheduler := newTestScheduler(t, &ScheduledUseCase{}, NewFakeClock())
+		require.NoError(t, scheduler.Add("cleanup", interactor.Every(time.Minute), TestRequest{}, &TestResponse{}))
+
+		// act
+		err := scheduler.Add("cleanup", interactor.Every(time.Hour), TestRequest{}, &TestResponse{})
+
+		// assert
+		require.ErrorIs(t, err, interactor.ErrDuplicateSchedule)
+	})
+
+	t.Run("the response must be a pointer", func(t *testing.T) {
+		t.Parallel()
+
+		scheduler := newTestScheduler(t, &ScheduledUseCase{}, NewFakeClock())
+
+		err := scheduler.Add("cleanup", interactor.Every(time.Minute), TestRequest{}, TestResponse{})
+		require.ErrorIs(t, err, interactor.ErrResultTypeMismatch)
+	})
+
+	t.Run("shutdown waits for the runs in progress", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		clock := NewFakeClock()
+
+		useCase := &ScheduledUseCase{blocked: make(chan struct{})}
+		scheduler := newTestScheduler(t, useCase, clock)
+		require.NoError(t, scheduler.Add("cleanup", interactor.Every(time.Minute), TestRequest{}, &TestResponse{}))
+		require.NoError(t, scheduler.Start())
+		waitForTimers(t, clock, 1)
+
+		clock.Advance(time.Minute)
+		require.Eventually(t, func() bool { return useCase.Runs() == 1 }, waitFor, tick)
+
+		time.AfterFunc(10*tick, func() { close(useCase.blocked) })
+
+		// act
+		err := scheduler.Shutdown(context.Background())
+
+		// assert
+		require.NoError(t, err)
+		assert.Zero(t, scheduler.Entries()[0].Running)
+		require.ErrorIs(t, scheduler.Start(), interactor.ErrSchedulerClosed)
+		require.ErrorIs(t,
+			scheduler.Add("report", interactor.Every(time.Minute), TestRequest{}, &TestResponse{}),
+			interactor.ErrSchedulerClosed,
+		)
+	})
+
+	t.Run("shutdown cancels the runs in progress once the context is done", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		clock := NewFakeClock()
+
+		useCase := &ScheduledUseCase{blocked: make(chan struct{})}
+		scheduler := newTestScheduler(t, useCase, clock)
+		require.NoError(t, scheduler.Add("cleanup", interactor.Every(time.Minute), TestRequest{}, &TestResponse{}))
+		require.NoError(t, scheduler.Start())
+		waitForTimers(t, clock, 1)
+
+		clock.Advance(time.Minute)
+		require.Eventually(t, func() bool { return useCase.Runs() == 1 }, waitFor, tick)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*tick)
+		defer cancel()
+
+		// act
+		err := scheduler.Shutdown(ctx)
+
+		// assert
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Eventually(t, func() bool {
+			return scheduler.Entries()[0].LastErr != nil
+		}, waitFor, tick)
+	})
+}
+
+// ScheduledUseCase counts its runs. If blocked is set, the runs block until it is closed or the context is done.
+type ScheduledUseCase struct {
+	mu      sync.Mutex
+	runs    int
+	blocked chan struct{}
+}
+
+func (uc *ScheduledUseCase) Run(ctx context.Context, _ TestRequest, _ *TestResponse) error {
+	uc.mu.Lock()
+	uc.runs++
+	uc.mu.Unlock()
+
+	if uc.blocked == nil {
+		return nil
+	}
+
+	select {
+	case <-uc.blocked:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (uc *ScheduledUseCase) Runs() int {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	return uc.runs
+}
+
+// newTestScheduler creates a scheduler running the use case, which is shut down at the end of the test.
+func newTestScheduler(t *testing.T, useCase *ScheduledUseCase, clock *FakeClock) *interactor.Scheduler {
+	t.Helper()
+
+	dispatcher := interactor.NewDispatcher()
+	dispatcher.Register(TestRequest{}, interactor.MustAdapt(useCase))
+
+	scheduler := interactor.NewScheduler(dispatcher, interactor.WithSchedulerClock(clock))
+	t.Cleanup(func() { _ = scheduler.Shutdown(context.Background()) })
+
+	return scheduler
+}
+
+// waitForTimers waits until the given number of timers is pending, so advancing the clock fires them.
+func waitForTimers(t *testing.T, clock *FakeClock, n int) {
+	t.Helper()
+
+	require.Eventually(t, func() bool { return clock.Timers() == n }, waitFor, tick)
+}
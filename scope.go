@@ -0,0 +1,102 @@
+package interactor
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+type scopeCtxKey struct{}
+
+// Scope holds the runners built by factories registered with PerScope and the cleanups of their dependencies,
+// e.g. for the lifetime of an HTTP request.
+type Scope struct {
+	mu       sync.Mutex
+	runners  map[*runnerFactory]*scopedRunner
+	cleanups []func(ctx context.Context) error
+	closed   bool
+}
+
+type scopedRunner struct {
+	once   sync.Once
+	runner UseCaseRunner
+	err    error
+}
+
+// NewScope creates a new Scope and returns a copy of the context carrying it.
+//
+// The scope must be closed once it is no longer used.
+func NewScope(ctx context.Context) (context.Context, *Scope) {
+	s := &Scope{runners: make(map[*runnerFactory]*scopedRunner)}
+
+	return context.WithValue(ctx, scopeCtxKey{}, s), s
+}
+
+// ScopeFromContext returns the scope stored in the context, if any.
+//
+// Factories use it to register the cleanups of the dependencies they create.
+func ScopeFromContext(ctx context.Context) (*Scope, bool) {
+	s, ok := ctx.Value(scopeCtxKey{}).(*Scope)
+
+	return s, ok
+}
+
+// Defer registers a cleanup to be run when the scope is closed.
+//
+// If the scope is already closed, the cleanup is run right away.
+func (s *Scope) Defer(ctx context.Context, cleanup func(ctx context.Context) error) error {
+	s.mu.Lock()
+	if !s.closed {
+		s.cleanups = append(s.cleanups, cleanup)
+		s.mu.Unlock()
+
+		return nil
+	}
+	s.mu.Unlock()
+
+	return cleanup(ctx)
+}
+
+// Close runs the cleanups in the reverse order they were registered.
+//
+// All of them are run even if some fail, the failures are joined into the returned error.
+// Closing a closed scope does nothing.
+func (s *Scope) Close(ctx context.Context) error {
+	s.mu.Lock()
+	cleanups := s.cleanups
+	s.cleanups, s.closed = nil, true
+	s.mu.Unlock()
+
+	var errs []error
+
+	for i := len(cleanups) - 1; i >= 0; i-- {
+		if err := cleanups[i](ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// runner returns the runner built by the factory for the scope, building it on the first call.
+func (s *Scope) runner(ctx context.Context, f *runnerFactory) (UseCaseRunner, error) {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+
+		return nil, ErrScopeClosed
+	}
+
+	scoped, ok := s.runners[f]
+	if !ok {
+		scoped = &scopedRunner{}
+		s.runners[f] = scoped
+	}
+	s.mu.Unlock()
+
+	scoped.once.Do(func() {
+		scoped.runner, scoped.err = f.build(ctx, s)
+	})
+
+	return scoped.runner, scoped.err
+}
@@ -0,0 +1,70 @@
+package interactor_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/interactor/v2"
+)
+
+func TestScope(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no scope is stored by default", func(t *testing.T) {
+		t.Parallel()
+
+		_, ok := interactor.ScopeFromContext(context.Background())
+		assert.False(t, ok)
+	})
+
+	t.Run("the cleanups are run in the reverse order and all the failures are returned", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		journal := &Journal{}
+		ctx, scope := interactor.NewScope(context.Background())
+
+		got, ok := interactor.ScopeFromContext(ctx)
+		require.True(t, ok)
+		assert.Same(t, scope, got)
+
+		require.NoError(t, scope.Defer(ctx, cleanup(journal, "first", errSomeErr)))
+		require.NoError(t, scope.Defer(ctx, cleanup(journal, "second", errUnhealthy)))
+
+		// act
+		err := scope.Close(ctx)
+
+		// assert
+		require.ErrorIs(t, err, errSomeErr)
+		require.ErrorIs(t, err, errUnhealthy)
+		assert.Equal(t, []string{"second", "first"}, journal.entries())
+	})
+
+	t.Run("a cleanup deferred after the scope is closed is run right away", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		journal := &Journal{}
+		ctx, scope := interactor.NewScope(context.Background())
+		require.NoError(t, scope.Close(ctx))
+
+		// act
+		err := scope.Defer(ctx, cleanup(journal, "late", errSomeErr))
+
+		// assert
+		require.ErrorIs(t, err, errSomeErr)
+		assert.Equal(t, []string{"late"}, journal.entries())
+		assert.NoError(t, scope.Close(ctx))
+	})
+}
+
+func cleanup(journal *Journal, name string, err error) func(context.Context) error {
+	return func(context.Context) error {
+		journal.record(name)
+
+		return err
+	}
+}
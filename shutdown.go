@@ -0,0 +1,188 @@
+package interactor
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// WithCancelOnShutdown makes Shutdown cancel the contexts of the use cases still running once its context is done.
+//
+// By default, they are left running.
+func WithCancelOnShutdown() DispatcherOption {
+	return func(d *Dispatcher) {
+		d.cancelOnShutdown = true
+	}
+}
+
+// ShutdownError reports the use cases which were still running when the shutdown timed out.
+type ShutdownError struct {
+	// Err is the error of the shutdown context.
+	Err error
+	// Running holds the number of the running use cases per request type.
+	Running map[reflect.Type]int
+}
+
+func (e *ShutdownError) Error() string {
+	running := make([]string, 0, len(e.Running))
+	for reqType, n := range e.Running {
+		running = append(running, fmt.Sprintf("%s (%d)", reqType, n))
+	}
+
+	sort.Strings(running)
+
+	return fmt.Sprintf("dispatcher shutdown: %v: still running: %s", e.Err, strings.Join(running, ", "))
+}
+
+func (e *ShutdownError) Unwrap() error {
+	return e.Err
+}
+
+// Shutdown stops the dispatcher from accepting new dispatches and waits for the running use cases to complete.
+//
+// Once it is called, Run returns ErrDispatcherClosed, except for the nested dispatches of the running use cases,
+// so they can complete. If the context is done first, *ShutdownError is returned reporting the request types
+// still running. Their contexts are cancelled if the dispatcher is created with WithCancelOnShutdown.
+func (d *Dispatcher) Shutdown(ctx context.Context) error {
+	drained := d.flights.close()
+
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+	}
+
+	running := d.flights.cancel(d.cancelOnShutdown)
+	if len(running) == 0 {
+		// The last use case completed at the last moment.
+		return nil
+	}
+
+	return &ShutdownError{Err: ctx.Err(), Running: running}
+}
+
+// InFlight returns the number of the running use cases per request type.
+func (d *Dispatcher) InFlight() map[reflect.Type]int {
+	return d.flights.running()
+}
+
+type dispatchCtxKey struct{}
+
+// inFlightRun is a use case being run.
+type inFlightRun struct {
+	reqType reflect.Type
+	cancel  context.CancelFunc
+}
+
+// inFlightTracker tracks the running use cases of a dispatcher.
+type inFlightTracker struct {
+	mu      sync.Mutex
+	closed  bool
+	runs    map[*inFlightRun]struct{}
+	drained chan struct{}
+}
+
+// begin registers a new run unless the dispatcher is closed. Nested runs are registered even then.
+func (t *inFlightTracker) begin(
+	ctx context.Context, d *Dispatcher, reqType reflect.Type,
+) (context.Context, *inFlightRun, error) {
+	nested := ctx.Value(dispatchCtxKey{}) == d
+
+	run := &inFlightRun{reqType: reqType}
+	if d.cancelOnShutdown {
+		ctx, run.cancel = context.WithCancel(ctx)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.closed && !nested {
+		if run.cancel != nil {
+			run.cancel()
+		}
+
+		return ctx, nil, fmt.Errorf("%w: %s", ErrDispatcherClosed, reqType)
+	}
+
+	if t.runs == nil {
+		t.runs = make(map[*inFlightRun]struct{})
+	}
+
+	t.runs[run] = struct{}{}
+
+	if !nested {
+		ctx = context.WithValue(ctx, dispatchCtxKey{}, d)
+	}
+
+	return ctx, run, nil
+}
+
+func (t *inFlightTracker) end(run *inFlightRun) {
+	if run.cancel != nil {
+		run.cancel()
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.runs, run)
+
+	if t.closed && len(t.runs) == 0 && t.drained != nil {
+		close(t.drained)
+		t.drained = nil
+	}
+}
+
+// close stops accepting new runs and returns a channel which is closed once all the runs complete.
+func (t *inFlightTracker) close() <-chan struct{} {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.closed = true
+
+	if len(t.runs) == 0 {
+		drained := make(chan struct{})
+		close(drained)
+
+		return drained
+	}
+
+	if t.drained == nil {
+		t.drained = make(chan struct{})
+	}
+
+	return t.drained
+}
+
+// cancel returns the running use cases, cancelling their contexts if requested.
+func (t *inFlightTracker) cancel(cancelRuns bool) map[reflect.Type]int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if cancelRuns {
+		for run := range t.runs {
+			run.cancel()
+		}
+	}
+
+	return t.countLocked()
+}
+
+func (t *inFlightTracker) running() map[reflect.Type]int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.countLocked()
+}
+
+func (t *inFlightTracker) countLocked() map[reflect.Type]int {
+	running := make(map[reflect.Type]int)
+	for run := range t.runs {
+		running[run.reqType]++
+	}
+
+	return running
+}
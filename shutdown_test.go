@@ -0,0 +1,154 @@
+package interactor_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/interactor/v2"
+)
+
+func TestDispatcherShutdown(t *testing.T) {
+	t.Parallel()
+
+	testRequestType := reflect.TypeOf(TestRequest{})
+
+	t.Run("new dispatches are rejected after shutdown", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		dispatcher := interactor.NewDispatcher()
+		dispatcher.Register(TestRequest{}, succeedingRunner)
+
+		// act
+		err := dispatcher.Shutdown(context.Background())
+
+		// assert
+		require.NoError(t, err)
+		require.ErrorIs(t, dispatcher.Run(context.Background(), TestRequest{}, &TestResponse{}), interactor.ErrDispatcherClosed)
+	})
+
+	t.Run("shutdown waits for the running use cases", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		useCase := NewBlockingUseCase()
+		dispatcher := interactor.NewDispatcher()
+		dispatcher.Register(TestRequest{}, interactor.MustAdapt(useCase))
+
+		done := make(chan error)
+
+		go func() { done <- dispatcher.Run(context.Background(), TestRequest{id: 123}, &TestResponse{}) }()
+
+		useCase.Started(1)
+		require.Equal(t, map[reflect.Type]int{testRequestType: 1}, dispatcher.InFlight())
+
+		time.AfterFunc(10*tick, useCase.Release)
+
+		// act
+		err := dispatcher.Shutdown(context.Background())
+
+		// assert
+		require.NoError(t, err)
+		require.NoError(t, <-done)
+		assert.Empty(t, dispatcher.InFlight())
+	})
+
+	t.Run("the request types still running are reported once the context is done", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		useCase := NewBlockingUseCase()
+		dispatcher := interactor.NewDispatcher()
+		dispatcher.Register(TestRequest{}, interactor.MustAdapt(useCase))
+
+		done := make(chan error, 2)
+
+		for i := 0; i < 2; i++ {
+			go func() { done <- dispatcher.Run(context.Background(), TestRequest{}, &TestResponse{}) }()
+		}
+
+		useCase.Started(2)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*tick)
+		defer cancel()
+
+		// act
+		err := dispatcher.Shutdown(ctx)
+
+		// assert
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		var shutdownErr *interactor.ShutdownError
+		require.True(t, errors.As(err, &shutdownErr))
+		assert.Equal(t, map[reflect.Type]int{testRequestType: 2}, shutdownErr.Running)
+		assert.EqualError(t, err,
+			"dispatcher shutdown: context deadline exceeded: still running: interactor_test.TestRequest (2)")
+
+		// the use cases are not cancelled by default
+		useCase.Release()
+		require.NoError(t, <-done)
+		require.NoError(t, <-done)
+	})
+
+	t.Run("the running use cases are cancelled if requested", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		useCase := NewBlockingUseCase()
+		dispatcher := interactor.NewDispatcher(interactor.WithCancelOnShutdown())
+		dispatcher.Register(TestRequest{}, interactor.MustAdapt(useCase))
+
+		done := make(chan error)
+
+		go func() { done <- dispatcher.Run(context.Background(), TestRequest{}, &TestResponse{}) }()
+
+		useCase.Started(1)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*tick)
+		defer cancel()
+
+		// act
+		err := dispatcher.Shutdown(ctx)
+
+		// assert
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.ErrorIs(t, <-done, context.Canceled)
+	})
+
+	t.Run("the nested dispatches of the running use cases are accepted", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		shuttingDown := make(chan struct{})
+		started := make(chan struct{})
+
+		dispatcher := interactor.NewDispatcher()
+		dispatcher.Register(PlaceOrder{}, interactor.MustAdapt(&PlaceOrderUseCase{}))
+		dispatcher.Register(TestRequest{}, func(ctx context.Context, _ interactor.Request, _ interactor.Response) error {
+			close(started)
+			<-shuttingDown
+
+			return dispatcher.Run(ctx, PlaceOrder{OrderID: 123}, &PlaceOrderResponse{})
+		})
+
+		done := make(chan error)
+
+		go func() { done <- dispatcher.Run(context.Background(), TestRequest{}, &TestResponse{}) }()
+
+		<-started
+		time.AfterFunc(10*tick, func() { close(shuttingDown) })
+
+		// act
+		err := dispatcher.Shutdown(context.Background())
+
+		// assert
+		require.NoError(t, err)
+		require.NoError(t, <-done)
+	})
+}
@@ -0,0 +1,85 @@
+package interactor
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// TxBeginner begins database transactions.
+//
+// It is implemented by *sql.DB and *sql.Conn.
+type TxBeginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+type txCtxKey struct{}
+
+// TxFromContext returns the transaction the use case runs in, if any.
+//
+// Repositories use it to take part in the unit of work:
+//
+//	tx, ok := interactor.TxFromContext(ctx)
+//	if !ok {
+//		return errors.New("no transaction")
+//	}
+//
+//	_, err := tx.ExecContext(ctx, "INSERT INTO users (name) VALUES (?)", name)
+func TxFromContext(ctx context.Context) (*sql.Tx, bool) {
+	tx, ok := ctx.Value(txCtxKey{}).(*sql.Tx)
+
+	return tx, ok
+}
+
+// WithTx returns a copy of the context carrying the given transaction.
+func WithTx(ctx context.Context, tx *sql.Tx) context.Context {
+	return context.WithValue(ctx, txCtxKey{}, tx)
+}
+
+// Transactional returns a middleware which runs a use case inside a database transaction.
+//
+// The transaction is stored in the context, see TxFromContext. It is committed if the use case succeeds
+// and rolled back if it returns an error or panics.
+//
+// If the context already carries a transaction, e.g. when a use case dispatches another one,
+// the nested run joins it and the outermost run decides whether it is committed.
+func Transactional(db TxBeginner, opts *sql.TxOptions) Middleware {
+	return func(next UseCaseRunnerFn) UseCaseRunnerFn {
+		return func(ctx context.Context, req Request, resp Response) error {
+			if _, ok := TxFromContext(ctx); ok {
+				return next(ctx, req, resp)
+			}
+
+			tx, err := db.BeginTx(ctx, opts)
+			if err != nil {
+				return fmt.Errorf("cannot begin transaction: %w", err)
+			}
+
+			defer func() {
+				if r := recover(); r != nil {
+					_ = tx.Rollback()
+					panic(r)
+				}
+			}()
+
+			if err := next(WithTx(ctx, tx), req, resp); err != nil {
+				return errors.Join(err, rollback(tx))
+			}
+
+			if err := tx.Commit(); err != nil {
+				return fmt.Errorf("cannot commit transaction: %w", err)
+			}
+
+			return nil
+		}
+	}
+}
+
+func rollback(tx *sql.Tx) error {
+	if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+		return fmt.Errorf("cannot rollback transaction: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,169 @@
+package interactor_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/screwyprof/interactor/v2"
+)
+
+func TestTransactional(t *testing.T) {
+	t.Parallel()
+
+	t.Run("when the use case succeeds, the transaction is committed", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		fake := &FakeDB{}
+		db := fake.Open()
+
+		var tx *sql.Tx
+		runner := interactor.Chain(
+			func(ctx context.Context, req interactor.Request, resp interactor.Response) error {
+				tx, _ = interactor.TxFromContext(ctx)
+
+				return nil
+			},
+			interactor.Transactional(db, nil),
+		)
+
+		// act
+		err := runner(context.Background(), TestRequest{}, &TestResponse{})
+
+		// assert
+		require.NoError(t, err)
+		assert.NotNil(t, tx)
+		assertTransactions(t, fake, 1, 1, 0)
+	})
+
+	t.Run("when the use case fails, the transaction is rolled back", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		fake := &FakeDB{}
+		runner := interactor.Chain(interactor.MustAdapt(ConcreteUseCase{err: errSomeErr}), interactor.Transactional(fake.Open(), nil))
+
+		// act
+		err := runner(context.Background(), TestRequest{}, &TestResponse{})
+
+		// assert
+		require.ErrorIs(t, err, errSomeErr)
+		assertTransactions(t, fake, 1, 0, 1)
+	})
+
+	t.Run("when the use case panics, the transaction is rolled back", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		fake := &FakeDB{}
+		runner := interactor.Chain(
+			func(context.Context, interactor.Request, interactor.Response) error { panic("boom") },
+			interactor.Transactional(fake.Open(), nil),
+		)
+
+		// act
+		act := func() { _ = runner(context.Background(), TestRequest{}, &TestResponse{}) }
+
+		// assert
+		assert.PanicsWithValue(t, "boom", act)
+		assertTransactions(t, fake, 1, 0, 1)
+	})
+
+	t.Run("when the transaction cannot begin, the use case is not run", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		fake := &FakeDB{failBegin: true}
+		useCase := &CountingUseCase{}
+		runner := interactor.Chain(interactor.MustAdapt(useCase), interactor.Transactional(fake.Open(), nil))
+
+		// act
+		err := runner(context.Background(), CacheableRequest{}, &DetailedResponse{})
+
+		// assert
+		require.ErrorIs(t, err, errFakeDB)
+		assert.Zero(t, useCase.Runs())
+	})
+
+	t.Run("when the transaction cannot be committed, an error is returned", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		fake := &FakeDB{failCommit: true}
+		runner := interactor.Chain(interactor.MustAdapt(ConcreteUseCase{}), interactor.Transactional(fake.Open(), nil))
+
+		// act
+		err := runner(context.Background(), TestRequest{}, &TestResponse{})
+
+		// assert
+		require.ErrorIs(t, err, errFakeDB)
+	})
+
+	t.Run("nested dispatches join the outer transaction", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		fake := &FakeDB{}
+		db := fake.Open()
+
+		var outerTx, innerTx *sql.Tx
+
+		dispatcher := interactor.NewDispatcher()
+		dispatcher.Use(interactor.Transactional(db, nil))
+		dispatcher.Register(&TestRequest{}, func(ctx context.Context, req interactor.Request, resp interactor.Response) error {
+			innerTx, _ = interactor.TxFromContext(ctx)
+
+			return nil
+		})
+		dispatcher.Register(TestRequest{}, func(ctx context.Context, req interactor.Request, resp interactor.Response) error {
+			outerTx, _ = interactor.TxFromContext(ctx)
+
+			return dispatcher.Run(ctx, &TestRequest{}, resp)
+		})
+
+		// act
+		err := dispatcher.Run(context.Background(), TestRequest{}, &TestResponse{})
+
+		// assert
+		require.NoError(t, err)
+		assert.NotNil(t, outerTx)
+		assert.Same(t, outerTx, innerTx)
+		assertTransactions(t, fake, 1, 1, 0)
+	})
+
+	t.Run("when a nested dispatch fails, the outer run decides on the transaction", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		fake := &FakeDB{}
+
+		dispatcher := interactor.NewDispatcher()
+		dispatcher.Use(interactor.Transactional(fake.Open(), nil))
+		dispatcher.Register(&TestRequest{}, func(context.Context, interactor.Request, interactor.Response) error {
+			return errSomeErr
+		})
+		dispatcher.Register(TestRequest{}, func(ctx context.Context, req interactor.Request, resp interactor.Response) error {
+			return dispatcher.Run(ctx, &TestRequest{}, resp)
+		})
+
+		// act
+		err := dispatcher.Run(context.Background(), TestRequest{}, &TestResponse{})
+
+		// assert
+		require.ErrorIs(t, err, errSomeErr)
+		assertTransactions(t, fake, 1, 0, 1)
+	})
+}
+
+func assertTransactions(t *testing.T, fake *FakeDB, wantBegins, wantCommits, wantRollbacks int) {
+	t.Helper()
+
+	begins, commits, rollbacks := fake.Stats()
+	assert.Equal(t, wantBegins, begins, "begins")
+	assert.Equal(t, wantCommits, commits, "commits")
+	assert.Equal(t, wantRollbacks, rollbacks, "rollbacks")
+}
@@ -0,0 +1,279 @@
+package interactor
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"reflect"
+	"sort"
+	"strconv"
+	"unsafe"
+)
+
+var errValueNotHashable = errors.New("value cannot be hashed")
+
+// hashValue returns a canonical hash of the given value.
+//
+// Equal values have equal hashes regardless of pointer identity or map ordering.
+// Values containing functions, channels or unsafe pointers cannot be hashed.
+func hashValue(v interface{}) (string, error) {
+	h := sha256.New()
+
+	if err := writeValue(h, reflect.ValueOf(v), make(map[uintptr]bool)); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func writeValue(h hash.Hash, v reflect.Value, visiting map[uintptr]bool) error {
+	if !v.IsValid() {
+		_, _ = h.Write([]byte("nil;"))
+
+		return nil
+	}
+
+	_, _ = fmt.Fprintf(h, "%s:", v.Type())
+
+	switch v.Kind() {
+	case reflect.Bool:
+		_, _ = h.Write(strconv.AppendBool(nil, v.Bool()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		_, _ = h.Write(strconv.AppendInt(nil, v.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		_, _ = h.Write(strconv.AppendUint(nil, v.Uint(), 10))
+	case reflect.Float32, reflect.Float64:
+		_, _ = h.Write(strconv.AppendFloat(nil, v.Float(), 'g', -1, 64))
+	case reflect.Complex64, reflect.Complex128:
+		_, _ = fmt.Fprint(h, v.Complex())
+	case reflect.String:
+		_, _ = fmt.Fprintf(h, "%q", v.String())
+	case reflect.Ptr:
+		return writePointer(h, v, visiting)
+	case reflect.Interface:
+		return writeValue(h, v.Elem(), visiting)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			_, _ = fmt.Fprintf(h, "%s=", v.Type().Field(i).Name)
+
+			if err := writeValue(h, v.Field(i), visiting); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		return writeElems(h, v, visiting)
+	case reflect.Map:
+		return writeMap(h, v, visiting)
+	default:
+		return fmt.Errorf("%w: %s", errValueNotHashable, v.Type())
+	}
+
+	_, _ = h.Write([]byte(";"))
+
+	return nil
+}
+
+func writePointer(h hash.Hash, v reflect.Value, visiting map[uintptr]bool) error {
+	if v.IsNil() {
+		_, _ = h.Write([]byte("nil;"))
+
+		return nil
+	}
+
+	if visiting[v.Pointer()] {
+		return fmt.Errorf("%w: %s is cyclic", errValueNotHashable, v.Type())
+	}
+
+	visiting[v.Pointer()] = true
+	defer delete(visiting, v.Pointer())
+
+	return writeValue(h, v.Elem(), visiting)
+}
+
+func writeElems(h hash.Hash, v reflect.Value, visiting map[uintptr]bool) error {
+	_, _ = fmt.Fprintf(h, "%d[", v.Len())
+
+	for i := 0; i < v.Len(); i++ {
+		if err := writeValue(h, v.Index(i), visiting); err != nil {
+			return err
+		}
+	}
+
+	_, _ = h.Write([]byte("];"))
+
+	return nil
+}
+
+func writeMap(h hash.Hash, v reflect.Value, visiting map[uintptr]bool) error {
+	entries := make([]string, 0, v.Len())
+
+	iter := v.MapRange()
+	for iter.Next() {
+		entry := sha256.New()
+
+		if err := writeValue(entry, iter.Key(), visiting); err != nil {
+			return err
+		}
+
+		if err := writeValue(entry, iter.Value(), visiting); err != nil {
+			return err
+		}
+
+		entries = append(entries, string(entry.Sum(nil)))
+	}
+
+	sort.Strings(entries)
+
+	_, _ = fmt.Fprintf(h, "%d{", len(entries))
+	for _, entry := range entries {
+		_, _ = h.Write([]byte(entry))
+	}
+	_, _ = h.Write([]byte("};"))
+
+	return nil
+}
+
+// newResponseLike allocates a new zero response of the same type as the given one.
+func newResponseLike(resp Response) (Response, error) {
+	t := reflect.TypeOf(resp)
+	if t == nil || t.Kind() != reflect.Ptr {
+		return nil, fmt.Errorf("%w: want a pointer, got %v", ErrResultTypeMismatch, t)
+	}
+
+	return reflect.New(t.Elem()).Interface(), nil
+}
+
+// copyResponse deep copies src into dst.
+//
+// Both responses must be non-nil pointers of the same type. Unexported fields are copied as well.
+func copyResponse(dst, src Response) error {
+	dv, sv := reflect.ValueOf(dst), reflect.ValueOf(src)
+
+	if dv.Kind() != reflect.Ptr || dv.IsNil() || sv.Kind() != reflect.Ptr || sv.IsNil() || dv.Type() != sv.Type() {
+		return fmt.Errorf("%w: want %T, got %T", ErrResultTypeMismatch, src, dst)
+	}
+
+	deepCopy(dv.Elem(), sv.Elem(), make(map[copiedPointer]reflect.Value))
+
+	return nil
+}
+
+// copiedPointer identifies a pointer already copied, so shared and cyclic pointers are copied once.
+type copiedPointer struct {
+	addr uintptr
+	typ  reflect.Type
+}
+
+func deepCopy(dst, src reflect.Value, copied map[copiedPointer]reflect.Value) {
+	dst, src = accessible(dst), accessible(src)
+
+	switch src.Kind() {
+	case reflect.Ptr:
+		deepCopyPointer(dst, src, copied)
+	case reflect.Interface:
+		if src.IsNil() {
+			dst.Set(reflect.Zero(dst.Type()))
+
+			return
+		}
+
+		elem := reflect.New(src.Elem().Type()).Elem()
+		deepCopy(elem, src.Elem(), copied)
+		dst.Set(elem)
+	case reflect.Struct:
+		src = addressable(src)
+		for i := 0; i < src.NumField(); i++ {
+			deepCopy(dst.Field(i), src.Field(i), copied)
+		}
+	case reflect.Slice:
+		if src.IsNil() {
+			dst.Set(reflect.Zero(dst.Type()))
+
+			return
+		}
+
+		s := reflect.MakeSlice(src.Type(), src.Len(), src.Len())
+		for i := 0; i < src.Len(); i++ {
+			deepCopy(s.Index(i), src.Index(i), copied)
+		}
+
+		dst.Set(s)
+	case reflect.Array:
+		src = addressable(src)
+		for i := 0; i < src.Len(); i++ {
+			deepCopy(dst.Index(i), src.Index(i), copied)
+		}
+	case reflect.Map:
+		deepCopyMap(dst, src, copied)
+	default:
+		dst.Set(src)
+	}
+}
+
+func deepCopyPointer(dst, src reflect.Value, copied map[copiedPointer]reflect.Value) {
+	if src.IsNil() {
+		dst.Set(reflect.Zero(dst.Type()))
+
+		return
+	}
+
+	key := copiedPointer{addr: src.Pointer(), typ: src.Type()}
+	if p, ok := copied[key]; ok {
+		dst.Set(p)
+
+		return
+	}
+
+	p := reflect.New(src.Type().Elem())
+	copied[key] = p
+
+	deepCopy(p.Elem(), src.Elem(), copied)
+	dst.Set(p)
+}
+
+func deepCopyMap(dst, src reflect.Value, copied map[copiedPointer]reflect.Value) {
+	if src.IsNil() {
+		dst.Set(reflect.Zero(dst.Type()))
+
+		return
+	}
+
+	m := reflect.MakeMapWithSize(src.Type(), src.Len())
+
+	iter := src.MapRange()
+	for iter.Next() {
+		k := reflect.New(src.Type().Key()).Elem()
+		deepCopy(k, iter.Key(), copied)
+
+		v := reflect.New(src.Type().Elem()).Elem()
+		deepCopy(v, iter.Value(), copied)
+
+		m.SetMapIndex(k, v)
+	}
+
+	dst.Set(m)
+}
+
+// addressable copies a value which cannot be addressed, e.g. stored in an interface or a map,
+// into a temporary one, so its unexported fields can be made accessible.
+func addressable(v reflect.Value) reflect.Value {
+	if v.CanAddr() {
+		return v
+	}
+
+	tmp := reflect.New(v.Type()).Elem()
+	tmp.Set(v)
+
+	return tmp
+}
+
+// accessible lifts the read-only restriction reflect puts on values reached through unexported fields.
+func accessible(v reflect.Value) reflect.Value {
+	if v.CanInterface() || !v.CanAddr() {
+		return v
+	}
+
+	return reflect.NewAt(v.Type(), unsafe.Pointer(v.UnsafeAddr())).Elem()
+}